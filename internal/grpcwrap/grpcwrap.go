@@ -0,0 +1,20 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+// Package grpcwrap holds the gRPC helpers shared by the metrics and trace interceptors.
+package grpcwrap
+
+import (
+	"strings"
+)
+
+// SplitFullMethod splits a gRPC full method name, i.e. /accelbyte.bans.v1.Bans/GetBan,
+// into its service and method names.
+func SplitFullMethod(fullMethod string) (service, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "unknown", fullMethod
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package grpcwrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitFullMethod(t *testing.T) {
+	service, method := SplitFullMethod("/accelbyte.bans.v1.Bans/GetBan")
+	assert.Equal(t, "accelbyte.bans.v1.Bans", service)
+	assert.Equal(t, "GetBan", method)
+
+	service, method = SplitFullMethod("GetBan")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "GetBan", method)
+}
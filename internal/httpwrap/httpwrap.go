@@ -0,0 +1,101 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+// Package httpwrap holds the net/http helpers shared by the metrics and trace middlewares.
+package httpwrap
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// StatusRecorder wraps an http.ResponseWriter to capture the response status code.
+type StatusRecorder struct {
+	http.ResponseWriter
+	StatusCode  int
+	wroteHeader bool
+}
+
+// NewStatusRecorder returns a StatusRecorder wrapping w, defaulting to http.StatusOK
+// when the handler never calls WriteHeader, and the writer to pass to the handler. The writer
+// implements http.Flusher and http.Hijacker only when w does, so the handlers checking them
+// see the same capabilities as without the recorder.
+func NewStatusRecorder(w http.ResponseWriter) (*StatusRecorder, http.ResponseWriter) {
+	r := &StatusRecorder{ResponseWriter: w, StatusCode: http.StatusOK}
+	flusher, isFlusher := w.(http.Flusher)
+	hijacker, isHijacker := w.(http.Hijacker)
+	switch {
+	case isFlusher && isHijacker:
+		return r, flushHijackRecorder{r, flusher, hijacker}
+	case isFlusher:
+		return r, flushRecorder{r, flusher}
+	case isHijacker:
+		return r, hijackRecorder{r, hijacker}
+	default:
+		return r, r
+	}
+}
+
+func (r *StatusRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.StatusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer, used by http.ResponseController.
+func (r *StatusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// flushRecorder represents an internal StatusRecorder of a writer implementing http.Flusher
+type flushRecorder struct {
+	*StatusRecorder
+	flusher http.Flusher
+}
+
+func (r flushRecorder) Flush() {
+	r.wroteHeader = true
+	r.flusher.Flush()
+}
+
+// hijackRecorder represents an internal StatusRecorder of a writer implementing http.Hijacker
+type hijackRecorder struct {
+	*StatusRecorder
+	hijacker http.Hijacker
+}
+
+func (r hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.hijacker.Hijack()
+}
+
+// flushHijackRecorder represents an internal StatusRecorder of a writer implementing http.Flusher and http.Hijacker
+type flushHijackRecorder struct {
+	*StatusRecorder
+	flusher  http.Flusher
+	hijacker http.Hijacker
+}
+
+func (r flushHijackRecorder) Flush() {
+	r.wroteHeader = true
+	r.flusher.Flush()
+}
+
+func (r flushHijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return r.hijacker.Hijack()
+}
+
+// ServeMuxRoute returns the pattern registered in mux that matches the request,
+// or an empty string when no pattern matches.
+func ServeMuxRoute(mux *http.ServeMux, r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	return pattern
+}
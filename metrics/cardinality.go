@@ -0,0 +1,260 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// OverflowLabelValue is the value of every label of the series past the cardinality limit of a metric.
+	OverflowLabelValue = "__overflow__"
+
+	defaultMaxSeries = 1000
+
+	metricsNameFoldedObservations = "ab_cardinality_limiter_folded_observations_total"
+	labelMetric                   = "metric"
+)
+
+// CardinalityLimiterOpts represents the cardinality limiter configuration options.
+type CardinalityLimiterOpts struct {
+	// MaxSeries is the maximum number of distinct label sets of each metric, default is 1000.
+	MaxSeries int
+	// MaxSeriesPerMetric overrides MaxSeries for the metrics with the given names.
+	MaxSeriesPerMetric map[string]int
+}
+
+// CardinalityLimiter is a Provider wrapping another one to bound the number of series of each metric,
+// so that an unbounded label value, e.g. a raw user ID, cannot blow up the memory of the service and
+// of Prometheus. Once a metric reaches its maximum number of label sets, the new label sets are folded
+// into a single series whose label values are all OverflowLabelValue. Each folded observation is counted in
+// ab_cardinality_limiter_folded_observations_total, labelled by metric, and a warning is logged once per metric.
+//
+// The observable metrics are not limited since their label sets are bounded by their callback,
+// register them directly on the wrapped provider.
+type CardinalityLimiter struct {
+	provider Provider
+	opts     CardinalityLimiterOpts
+	folded   CounterVecMetric
+
+	mu     sync.Mutex
+	limits map[string]*seriesLimit
+}
+
+// NewCardinalityLimiter wraps provider in a CardinalityLimiter configured by opts.
+func NewCardinalityLimiter(provider Provider, opts CardinalityLimiterOpts) *CardinalityLimiter {
+	if opts.MaxSeries <= 0 {
+		opts.MaxSeries = defaultMaxSeries
+	}
+	return &CardinalityLimiter{
+		provider: provider,
+		opts:     opts,
+		folded: provider.NewCounter(metricsNameFoldedObservations,
+			"Number of observations folded into the overflow series of a metric past its cardinality limit", labelMetric),
+		limits: map[string]*seriesLimit{},
+	}
+}
+
+// NewCounter creates a new counter vector metric with the wrapped provider, limiting its cardinality.
+func (l *CardinalityLimiter) NewCounter(name, help string, labels ...string) CounterVecMetric {
+	return limitedCounterVec{l.provider.NewCounter(name, help, labels...), l.newSeriesLimit(name)}
+}
+
+// NewCounterWithOpts creates a new counter vector metric defined by opts with the wrapped provider,
+// limiting its cardinality. Only the name of opts is honored if the wrapped provider is not an OptsProvider.
+func (l *CardinalityLimiter) NewCounterWithOpts(name, help string, opts MetricOpts, labels ...string) CounterVecMetric {
+	p, ok := l.provider.(OptsProvider)
+	if !ok {
+		return l.NewCounter(opts.FullName(name), help, labels...)
+	}
+	return limitedCounterVec{p.NewCounterWithOpts(name, help, opts, labels...), l.newSeriesLimit(opts.FullName(name))}
+}
+
+// NewGauge creates a new gauge vector metric with the wrapped provider, limiting its cardinality.
+func (l *CardinalityLimiter) NewGauge(name, help string, labels ...string) GaugeVecMetric {
+	return limitedGaugeVec{l.provider.NewGauge(name, help, labels...), l.newSeriesLimit(name)}
+}
+
+// NewGaugeWithOpts creates a new gauge vector metric defined by opts with the wrapped provider,
+// limiting its cardinality. Only the name of opts is honored if the wrapped provider is not an OptsProvider.
+func (l *CardinalityLimiter) NewGaugeWithOpts(name, help string, opts MetricOpts, labels ...string) GaugeVecMetric {
+	p, ok := l.provider.(OptsProvider)
+	if !ok {
+		return l.NewGauge(opts.FullName(name), help, labels...)
+	}
+	return limitedGaugeVec{p.NewGaugeWithOpts(name, help, opts, labels...), l.newSeriesLimit(opts.FullName(name))}
+}
+
+// NewHistogram creates a new histogram vector metric with the wrapped provider, limiting its cardinality.
+func (l *CardinalityLimiter) NewHistogram(name, help string, buckets []float64, labels ...string) ObserverVecMetric {
+	return limitedObserverVec{l.provider.NewHistogram(name, help, buckets, labels...), l.newSeriesLimit(name)}
+}
+
+// NewNativeHistogram creates a new native histogram vector metric with the wrapped provider, limiting
+// its cardinality. The histogram is a classic one if the wrapped provider is not a NativeHistogramProvider.
+func (l *CardinalityLimiter) NewNativeHistogram(name, help string, buckets []float64, opts NativeHistogramOpts, labels ...string) ObserverVecMetric {
+	p, ok := l.provider.(NativeHistogramProvider)
+	if !ok {
+		return l.NewHistogram(name, help, buckets, labels...)
+	}
+	return limitedObserverVec{p.NewNativeHistogram(name, help, buckets, opts, labels...), l.newSeriesLimit(name)}
+}
+
+// NewHistogramWithOpts creates a new histogram vector metric defined by opts with the wrapped provider,
+// limiting its cardinality. Only the name and buckets of opts are honored if the wrapped provider
+// is not an OptsProvider.
+func (l *CardinalityLimiter) NewHistogramWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric {
+	p, ok := l.provider.(OptsProvider)
+	if !ok {
+		return l.NewHistogram(opts.FullName(name), help, opts.Buckets, labels...)
+	}
+	return limitedObserverVec{p.NewHistogramWithOpts(name, help, opts, labels...), l.newSeriesLimit(opts.FullName(name))}
+}
+
+// NewSummary creates a new summary vector metric with the wrapped provider, limiting its cardinality.
+func (l *CardinalityLimiter) NewSummary(name, help string, labels ...string) ObserverVecMetric {
+	return limitedObserverVec{l.provider.NewSummary(name, help, labels...), l.newSeriesLimit(name)}
+}
+
+// NewSummaryWithOpts creates a new summary vector metric defined by opts with the wrapped provider,
+// limiting its cardinality. Only the name of opts is honored if the wrapped provider is not an OptsProvider.
+func (l *CardinalityLimiter) NewSummaryWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric {
+	p, ok := l.provider.(OptsProvider)
+	if !ok {
+		return l.NewSummary(opts.FullName(name), help, labels...)
+	}
+	return limitedObserverVec{p.NewSummaryWithOpts(name, help, opts, labels...), l.newSeriesLimit(opts.FullName(name))}
+}
+
+// Unwrap returns the wrapped provider.
+func (l *CardinalityLimiter) Unwrap() Provider {
+	return l.provider
+}
+
+// Unregister unregisters the metric name from the wrapped provider. It returns false if there is no such metric
+// or if the wrapped provider is not an UnregisterProvider.
+func (l *CardinalityLimiter) Unregister(name string) bool {
+	if p, ok := l.provider.(UnregisterProvider); ok {
+		return p.Unregister(name)
+	}
+	return false
+}
+
+// newSeriesLimit returns the series limit of the metric name, shared by every vector created for that name
+// so that creating the same metric again does not reset its label sets.
+func (l *CardinalityLimiter) newSeriesLimit(name string) *seriesLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limit, ok := l.limits[name]; ok {
+		return limit
+	}
+	maxSeries, ok := l.opts.MaxSeriesPerMetric[name]
+	if !ok {
+		maxSeries = l.opts.MaxSeries
+	}
+	limit := &seriesLimit{
+		name:      name,
+		maxSeries: maxSeries,
+		seen:      map[string]struct{}{},
+		folded:    l.folded.With(map[string]string{labelMetric: name}),
+	}
+	l.limits[name] = limit
+	return limit
+}
+
+// seriesLimit tracks the label sets of one metric.
+type seriesLimit struct {
+	name      string
+	maxSeries int
+	folded    CounterMetric
+	warnOnce  sync.Once
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// limit returns labels if the label set is known or the limit is not reached yet, or else the overflow label set.
+func (l *seriesLimit) limit(labels map[string]string) map[string]string {
+	key := seriesKey(labels)
+
+	l.mu.Lock()
+	_, known := l.seen[key]
+	if !known && len(l.seen) < l.maxSeries {
+		l.seen[key] = struct{}{}
+		known = true
+	}
+	l.mu.Unlock()
+	if known {
+		return labels
+	}
+
+	l.warnOnce.Do(func() {
+		log.Printf("metric %s reached its limit of %d label sets, the new label sets are recorded as %s",
+			l.name, l.maxSeries, OverflowLabelValue)
+	})
+	l.folded.Inc()
+
+	overflow := make(map[string]string, len(labels))
+	for label := range labels {
+		overflow[label] = OverflowLabelValue
+	}
+	return overflow
+}
+
+// seriesKey identifies a label set whatever the order of the labels.
+func seriesKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\xff")
+}
+
+// limitedCounterVec represents an internal counter vec type that implements CounterVecMetric with a cardinality limit
+type limitedCounterVec struct {
+	vec   CounterVecMetric
+	limit *seriesLimit
+}
+
+func (c limitedCounterVec) With(labels map[string]string) CounterMetric {
+	return c.vec.With(c.limit.limit(labels))
+}
+
+func (c limitedCounterVec) registrationError() error {
+	return MetricRegistrationError(c.vec)
+}
+
+// limitedGaugeVec represents an internal gauge vec type that implements GaugeVecMetric with a cardinality limit
+type limitedGaugeVec struct {
+	vec   GaugeVecMetric
+	limit *seriesLimit
+}
+
+func (g limitedGaugeVec) With(labels map[string]string) GaugeMetric {
+	return g.vec.With(g.limit.limit(labels))
+}
+
+func (g limitedGaugeVec) registrationError() error {
+	return MetricRegistrationError(g.vec)
+}
+
+// limitedObserverVec represents an internal observer vec type that implements ObserverVecMetric with a cardinality limit
+type limitedObserverVec struct {
+	vec   ObserverVecMetric
+	limit *seriesLimit
+}
+
+func (o limitedObserverVec) With(labels map[string]string) ObserverMetric {
+	return o.vec.With(o.limit.limit(labels))
+}
+
+func (o limitedObserverVec) registrationError() error {
+	return MetricRegistrationError(o.vec)
+}
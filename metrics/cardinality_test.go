@@ -0,0 +1,56 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCardinalityLimiter(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	limiter := NewCardinalityLimiter(provider, CardinalityLimiterOpts{
+		MaxSeries:          2,
+		MaxSeriesPerMetric: map[string]int{"ab_test_logins": 1},
+	})
+
+	requests := limiter.NewCounter("ab_test_requests", "requests", "user")
+	for _, user := range []string{"alice", "bob", "carol", "dave", "alice"} {
+		requests.With(map[string]string{"user": user}).Inc()
+	}
+	logins := limiter.NewHistogramWithOpts("logins", "logins", MetricOpts{Namespace: "ab_test"}, "user")
+	logins.With(map[string]string{"user": "alice"}).Observe(1)
+	logins.With(map[string]string{"user": "bob"}).Observe(1)
+
+	vec := requests.(limitedCounterVec).vec.(counterVec)
+	assert.Equal(t, 2.0, testutil.ToFloat64(vec.WithLabelValues("alice")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(vec.WithLabelValues("bob")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(vec.WithLabelValues(OverflowLabelValue)))
+	assert.Equal(t, 3, testutil.CollectAndCount(vec))
+	assert.Equal(t, 2, testutil.CollectAndCount(logins.(limitedObserverVec).vec.(histogramVec)))
+
+	folded := limiter.folded.(counterVec)
+	assert.Equal(t, 2.0, testutil.ToFloat64(folded.WithLabelValues("ab_test_requests")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(folded.WithLabelValues("ab_test_logins")))
+}
+
+func TestCardinalityLimiterSharesLimitPerMetric(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	limiter := NewCardinalityLimiter(provider, CardinalityLimiterOpts{MaxSeries: 1})
+
+	limiter.NewCounter("ab_test_requests", "requests", "user").With(map[string]string{"user": "alice"}).Inc()
+	requests := limiter.NewCounterWithOpts("requests", "requests", MetricOpts{Namespace: "ab_test"}, "user")
+	requests.With(map[string]string{"user": "bob"}).Inc()
+
+	vec := requests.(limitedCounterVec).vec.(counterVec)
+	assert.Equal(t, 1.0, testutil.ToFloat64(vec.WithLabelValues("alice")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(vec.WithLabelValues(OverflowLabelValue)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(limiter.folded.(counterVec).WithLabelValues("ab_test_requests")))
+}
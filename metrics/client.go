@@ -0,0 +1,130 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+// Client holds the metrics state of one service: its provider, HTTP metrics and runtime metrics.
+// Several clients can live in the same binary as long as each one owns its Provider.
+type Client struct {
+	provider               Provider
+	serviceName            string
+	namespacePathParameter string
+	enableRuntimeMetrics   bool
+	nativeHistograms       *NativeHistogramOpts
+
+	httpMetrics       ObserverVecMetric
+	httpClientMetrics ObserverVecMetric
+	grpcServerMetrics ObserverVecMetric
+	grpcClientMetrics ObserverVecMetric
+
+	runtimeMetricsGaugeMap map[string]GaugeMetric
+	// runtimeMetricsQuantileMap holds the runtime histograms sent as quantile gauges to the non Prometheus providers
+	runtimeMetricsQuantileMap map[string]GaugeVecMetric
+	stopRuntimeMetrics        func()
+}
+
+// NewClient creates a new metrics client for service s.
+// If option is nil or option.Provider is empty, DefaultProvider is used.
+func NewClient(s string, buildInfo BuildInfo, option *Opts) *Client {
+	c := &Client{
+		provider:               DefaultProvider,
+		serviceName:            s,
+		namespacePathParameter: defaultNamespacePathParameter,
+		enableRuntimeMetrics:   true,
+	}
+
+	if option != nil {
+		c.overrideDefaultOption(option)
+	}
+
+	if c.httpMetrics == nil {
+		c.httpMetrics = newHistogram(c.provider, c.nativeHistograms,
+			generateMetricsName(genericServiceName, metricsNameHTTP),
+			"HTTP request in histogram",
+			defaultLatencyBuckets,
+			labelNamespace, labelPath, labelMethod, labelResponseCode,
+		)
+	}
+	c.httpClientMetrics = newHistogram(c.provider, c.nativeHistograms,
+		generateMetricsName(genericServiceName, metricsNameHTTPClient),
+		"HTTP client request in histogram",
+		defaultLatencyBuckets,
+		labelHost, labelMethod, labelResponseCode,
+	)
+	c.grpcServerMetrics = newHistogram(c.provider, c.nativeHistograms,
+		generateMetricsName(genericServiceName, metricsNameGRPCServer),
+		"gRPC server request in histogram",
+		defaultLatencyBuckets,
+		labelService, labelMethod, labelCode,
+	)
+	c.grpcClientMetrics = newHistogram(c.provider, c.nativeHistograms,
+		generateMetricsName(genericServiceName, metricsNameGRPCClient),
+		"gRPC client request in histogram",
+		defaultLatencyBuckets,
+		labelService, labelMethod, labelCode,
+	)
+
+	c.initBuildInfo(buildInfo)
+
+	if c.enableRuntimeMetrics {
+		c.startRuntimeMetrics()
+	}
+
+	return c
+}
+
+func (c *Client) overrideDefaultOption(option *Opts) {
+	if option.Provider != nil {
+		c.provider = option.Provider
+	}
+	if option.NamespacePath != "" {
+		c.namespacePathParameter = option.NamespacePath
+	}
+	if !option.EnableRuntimeMetrics {
+		c.enableRuntimeMetrics = false
+	}
+	c.nativeHistograms = option.NativeHistograms
+	if option.CustomHTTPMetrics != nil {
+		c.httpMetrics = *option.CustomHTTPMetrics
+	}
+}
+
+// initBuildInfo initializes one gauge metric with constant 1
+func (c *Client) initBuildInfo(buildInfo BuildInfo) {
+	c.provider.NewGauge(
+		generateMetricsName(c.serviceName, "build_info"),
+		"A metric with a constant '1' value labeled by version, revision, branch, and goversion from which the service was built",
+		"revisionID", "buildDate", "version", "gitHash", "roleSeedingVersion",
+	).With(map[string]string{
+		"revisionID":         buildInfo.RevisionID,
+		"buildDate":          buildInfo.BuildDate,
+		"version":            buildInfo.Version,
+		"gitHash":            buildInfo.GitHash,
+		"roleSeedingVersion": buildInfo.RoleSeedingVersion,
+	}).Set(1)
+}
+
+// initialized reports whether the client was created by NewClient, which is not the case of the default
+// client until Initialize is called.
+func (c *Client) initialized() bool {
+	return c.provider != nil
+}
+
+// Provider returns the metrics provider owned by the client.
+func (c *Client) Provider() Provider {
+	return c.provider
+}
+
+// NewDBMetrics returns new DB metrics using the client provider and service name.
+func (c *Client) NewDBMetrics(dbName string, labels ...string) *DBMetrics {
+	return newDBMetrics(c.provider, c.serviceName, c.nativeHistograms, dbName, labels...)
+}
+
+// newHistogram creates a histogram with provider, native if nativeHistograms is set and the provider supports it.
+func newHistogram(provider Provider, nativeHistograms *NativeHistogramOpts, name, help string, buckets []float64, labels ...string) ObserverVecMetric {
+	if nativeProvider, ok := provider.(NativeHistogramProvider); ok && nativeHistograms != nil {
+		return nativeProvider.NewNativeHistogram(name, help, buckets, *nativeHistograms, labels...)
+	}
+	return provider.NewHistogram(name, help, buckets, labels...)
+}
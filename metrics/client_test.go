@@ -0,0 +1,75 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestClientsDoNotShareState(t *testing.T) {
+	newClient := func(service string) (*Client, *prometheus.Registry) {
+		registry := prometheus.NewRegistry()
+		provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+		return NewClient(service, BuildInfo{}, &Opts{Provider: provider, NamespacePath: service}), registry
+	}
+	clientA, registryA := newClient("service_a")
+	clientB, registryB := newClient("service_b")
+
+	clientA.NewDBMetrics("bans").NewCall("get_ban").CallEnded()
+
+	assert.Equal(t, "service_a", clientA.namespacePathParameter)
+	assert.Equal(t, "service_b", clientB.namespacePathParameter)
+	assert.Equal(t, 1, testutil.CollectAndCount(registryA, "ab_service_a_bans_db_latency_seconds"))
+	assert.Equal(t, 0, testutil.CollectAndCount(registryB, "ab_service_a_bans_db_latency_seconds"))
+}
+
+func TestClientNativeHistograms(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{
+		Provider:         provider,
+		NativeHistograms: &NativeHistogramOpts{ClassicBuckets: true},
+	})
+
+	client.NewDBMetrics("bans").NewCall("get_ban").CallEnded()
+
+	histogram := gatherHistogram(t, registry, "ab_test_bans_db_latency_seconds")
+	assert.Len(t, histogram.GetBucket(), len(prometheus.DefBuckets))
+	assert.NotEmpty(t, histogram.GetPositiveSpan())
+}
+
+func TestDefaultClientBeforeInitialize(t *testing.T) {
+	previousClient := defaultClient
+	defer func() {
+		defaultClient = previousClient
+	}()
+	defaultClient = &Client{}
+
+	server := httptest.NewServer(HTTPMiddleware(HTTPMiddlewareOpts{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer server.Close()
+
+	resp, err := (&http.Client{Transport: NewTransport(nil)}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	reply, err := UnaryServerInterceptor()(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/bans.Bans/GetBan"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "resp", nil
+		})
+	require.NoError(t, err)
+	assert.Equal(t, "resp", reply)
+}
@@ -5,9 +5,12 @@
 package metrics
 
 const (
-	metricsNameFormat  = "ab.%s_%s" // ab is the namespace and the next two placeholder is for service name and metrics name
-	metricsNameHTTP    = "request_http"
-	genericServiceName = "service"
+	metricsNameFormat     = "ab.%s_%s" // ab is the namespace and the next two placeholder is for service name and metrics name
+	metricsNameHTTP       = "request_http"
+	metricsNameHTTPClient = "client_request_http"
+	metricsNameGRPCServer = "request_grpc"
+	metricsNameGRPCClient = "client_request_grpc"
+	genericServiceName    = "service"
 
 	defaultNamespacePathParameter = "namespace"
 
@@ -15,4 +18,14 @@ const (
 	labelPath         = "path"
 	labelMethod       = "method"
 	labelResponseCode = "response_code"
+	labelService      = "service"
+	labelHost         = "host"
+	labelCode         = "code"
+	labelQuantile     = "quantile"
+
+	defaultNativeHistogramBucketFactor    = 1.1
+	defaultNativeHistogramMaxBucketNumber = 160
 )
+
+// defaultLatencyBuckets are the buckets used by the HTTP and gRPC request latency histograms.
+var defaultLatencyBuckets = []float64{0.001, 0.01, 0.1, 0.5, 1, 1.5, 2, 3, 4, 5, 7.5, 10, 15, 20}
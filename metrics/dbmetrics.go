@@ -5,6 +5,7 @@
 package metrics
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -23,10 +24,14 @@ const (
 
 type DBMetrics struct {
 	dbName          string
+	labels          []string
 	metricsProvider Provider
 	latencyMetrics  ObserverVecMetric
 }
 
+// PostgreDBMetrics records the connection pool stats as histograms when ObservePostgreDBMetric is called.
+//
+// Deprecated: use RegisterDBStatsCollector, which reads the stats at collection time for any driver.
 type PostgreDBMetrics struct {
 	MaxOpenConnections ObserverVecMetric
 	OpenConnections    ObserverVecMetric
@@ -41,22 +46,33 @@ type PostgreDBMetrics struct {
 
 // NewDBMetrics returns new DB metrics.
 func NewDBMetrics(metricsProvider Provider, dbName string, labels ...string) *DBMetrics {
+	return newDBMetrics(metricsProvider, defaultClient.serviceName, defaultClient.nativeHistograms, dbName, labels...)
+}
+
+func newDBMetrics(metricsProvider Provider, serviceName string, nativeHistograms *NativeHistogramOpts, dbName string, labels ...string) *DBMetrics {
 	l := []string{dbCallLabelAction, dbCallLabelResult}
 	if len(labels) > 0 {
 		l = append(l, labels...)
 	}
-	latencyMetrics := metricsProvider.NewHistogram(generateDBMetricsName(dbName),
+	latencyMetrics := newHistogram(metricsProvider, nativeHistograms, generateDBMetricsName(serviceName, dbName),
 		fmt.Sprintf("Latency of %s in seconds", dbName), prometheus.DefBuckets, l...)
-	return &DBMetrics{metricsProvider: metricsProvider, latencyMetrics: latencyMetrics}
+	return &DBMetrics{dbName: dbName, labels: labels, metricsProvider: metricsProvider, latencyMetrics: latencyMetrics}
 }
 
-func generateDBMetricsName(dbName string) string {
+func generateDBMetricsName(serviceName, dbName string) string {
 	return generateMetricsName(serviceName, fmt.Sprintf("%s_db_latency_seconds", dbName))
 }
 
 // NewCall returns a new DB call metrics and start it.
 func (d *DBMetrics) NewCall(action string) *dbCallMetrics {
+	return d.NewCallWithContext(context.Background(), action)
+}
+
+// NewCallWithContext returns a new DB call metrics and start it. The trace ID of the span of ctx
+// is attached as exemplar to the latency when the span is sampled.
+func (d *DBMetrics) NewCallWithContext(ctx context.Context, action string) *dbCallMetrics {
 	dbCall := &dbCallMetrics{
+		ctx:            ctx,
 		action:         action,
 		startTime:      time.Time{},
 		endTime:        time.Time{},
@@ -68,6 +84,7 @@ func (d *DBMetrics) NewCall(action string) *dbCallMetrics {
 }
 
 type dbCallMetrics struct {
+	ctx            context.Context
 	action         string
 	isError        bool
 	startTime      time.Time
@@ -100,7 +117,7 @@ func (e *dbCallMetrics) CallEnded() {
 	e.endTime = time.Now().UTC()
 	e.labelsMap[dbCallLabelAction] = e.action
 	e.labelsMap[dbCallLabelResult] = getResultLabelValue(e.isError)
-	e.latencyMetrics.With(e.labelsMap).Observe(e.elapsed().Seconds())
+	observeWithExemplar(e.ctx, e.latencyMetrics.With(e.labelsMap), e.elapsed().Seconds())
 }
 
 func getResultLabelValue(isError bool) string {
@@ -118,6 +135,8 @@ func (e *dbCallMetrics) elapsed() time.Duration {
 }
 
 // NewPostgreDBMetrics returns new PostgreDBMetrics.
+//
+// Deprecated: use RegisterDBStatsCollector instead.
 func NewPostgreDBMetrics(metricsProvider Provider, dbName string) *PostgreDBMetrics {
 	l := []string{dbMetricLabelInstance}
 	return &PostgreDBMetrics{
@@ -142,6 +161,9 @@ func NewPostgreDBMetrics(metricsProvider Provider, dbName string) *PostgreDBMetr
 	}
 }
 
+// ObservePostgreDBMetric records the current connection pool stats of db.
+//
+// Deprecated: use RegisterDBStatsCollector instead.
 func (dbMetric *PostgreDBMetrics) ObservePostgreDBMetric(dbType string, db *sql.DB) {
 	dbStats := db.Stats()
 	dbTypeLabel := map[string]string{dbMetricLabelInstance: dbType}
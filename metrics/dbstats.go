@@ -0,0 +1,127 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// registeredDBStats tracks the DB stats registered by every client, so that Initialize does not forget them.
+var registeredDBStats dbStatsSet
+
+// RegisterDBStatsCollector registers the connection pool stats of db, read at collection time,
+// with the default client service name. It works with any database/sql driver.
+// It returns an error if metricsProvider is not an ObservableProvider, or if dbName is already registered
+// with the same service name, and else a function unregistering the stats, e.g. when db is closed.
+func RegisterDBStatsCollector(metricsProvider Provider, db *sql.DB, dbName string) (func(), error) {
+	return registerDBStatsCollector(metricsProvider, defaultClient.serviceName, db, dbName)
+}
+
+// RegisterDBStatsCollector registers the connection pool stats of db, read at collection time,
+// with the client provider and service name. It returns an error if dbName is already registered
+// with the same service name, and else a function unregistering the stats.
+func (c *Client) RegisterDBStatsCollector(db *sql.DB, dbName string) (func(), error) {
+	return registerDBStatsCollector(c.provider, c.serviceName, db, dbName)
+}
+
+// dbStatsSet tracks the prefixes of the registered DB stats, the zero value is empty.
+type dbStatsSet struct {
+	mu       sync.Mutex
+	prefixes map[string]struct{}
+}
+
+// add adds prefix, and returns false if it is already in the set.
+func (s *dbStatsSet) add(prefix string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.prefixes[prefix]; ok {
+		return false
+	}
+	if s.prefixes == nil {
+		s.prefixes = map[string]struct{}{}
+	}
+	s.prefixes[prefix] = struct{}{}
+	return true
+}
+
+func (s *dbStatsSet) remove(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.prefixes, prefix)
+}
+
+// registerDBStatsCollector reports the current pool values as gauges and the cumulative ones as counters,
+// named ab.<service>_<db>_db_<stat> like the DB latency histogram. Once unregistered, the stats are
+// unregistered from the UnregisterProvider providers, and not observed anymore by the other ones.
+func registerDBStatsCollector(metricsProvider Provider, serviceName string, db *sql.DB, dbName string) (func(), error) {
+	p, ok := unwrapProvider(metricsProvider).(ObservableProvider)
+	if !ok {
+		return nil, fmt.Errorf("metrics provider %T does not support observable metrics", metricsProvider)
+	}
+	prefix := generateMetricsName(serviceName, dbName+"_db")
+	if !registeredDBStats.add(prefix) {
+		return nil, fmt.Errorf("db stats of %s are already registered", dbName)
+	}
+
+	var names []string
+	name := func(stat string) string {
+		n := prefix + "_" + stat
+		names = append(names, n)
+		return n
+	}
+	var unregistered atomic.Bool
+	stat := func(value func(sql.DBStats) float64) func() []Observation {
+		return func() []Observation {
+			if unregistered.Load() {
+				return nil
+			}
+			return []Observation{{Value: value(db.Stats())}}
+		}
+	}
+
+	p.NewObservableGauge(name("max_open_connections"),
+		fmt.Sprintf("Maximum open connections on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	p.NewObservableGauge(name("open_connections"),
+		fmt.Sprintf("Established connections both in use and idle on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	p.NewObservableGauge(name("in_use_connections"),
+		fmt.Sprintf("Connections currently in use on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	p.NewObservableGauge(name("idle_connections"),
+		fmt.Sprintf("Idle connections on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	p.NewObservableCounter(name("wait_count_total"),
+		fmt.Sprintf("Total connections waited for on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	p.NewObservableCounter(name("wait_duration_seconds_total"),
+		fmt.Sprintf("Total time blocked waiting for a new connection on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	p.NewObservableCounter(name("max_idle_closed_total"),
+		fmt.Sprintf("Total connections closed due to SetMaxIdleConns on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	p.NewObservableCounter(name("max_idle_time_closed_total"),
+		fmt.Sprintf("Total connections closed due to SetConnMaxIdleTime on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }))
+	p.NewObservableCounter(name("max_lifetime_closed_total"),
+		fmt.Sprintf("Total connections closed due to SetConnMaxLifetime on %s", dbName),
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unregistered.Store(true)
+			if up, ok := metricsProvider.(UnregisterProvider); ok {
+				for _, n := range names {
+					up.Unregister(n)
+				}
+			}
+			registeredDBStats.remove(prefix)
+		})
+	}, nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterDBStatsCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider})
+
+	db, err := sql.Open("metrics-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(5)
+
+	unregister, err := client.RegisterDBStatsCollector(db, "bans")
+	require.NoError(t, err)
+
+	expected := `
+# HELP ab_test_bans_db_max_open_connections Maximum open connections on bans
+# TYPE ab_test_bans_db_max_open_connections gauge
+ab_test_bans_db_max_open_connections 5
+# HELP ab_test_bans_db_open_connections Established connections both in use and idle on bans
+# TYPE ab_test_bans_db_open_connections gauge
+ab_test_bans_db_open_connections 1
+# HELP ab_test_bans_db_wait_count_total Total connections waited for on bans
+# TYPE ab_test_bans_db_wait_count_total counter
+ab_test_bans_db_wait_count_total 0
+`
+	require.NoError(t, db.Ping())
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"ab_test_bans_db_max_open_connections",
+		"ab_test_bans_db_open_connections",
+		"ab_test_bans_db_wait_count_total",
+	))
+
+	_, err = client.RegisterDBStatsCollector(db, "bans")
+	assert.Error(t, err)
+
+	unregister()
+	unregister()
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "ab_test_bans_db_max_open_connections"))
+	unregister, err = client.RegisterDBStatsCollector(db, "bans")
+	require.NoError(t, err)
+	defer unregister()
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "ab_test_bans_db_max_open_connections"))
+}
+
+func TestRegisterDBStatsCollectorNotObservable(t *testing.T) {
+	db, err := sql.Open("metrics-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = RegisterDBStatsCollector(struct{ Provider }{}, db, "bans")
+	assert.Error(t, err)
+}
+
+func TestRegisterDBStatsCollectorWrappedProvider(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	limiter := NewCardinalityLimiter(provider, CardinalityLimiterOpts{})
+
+	db, err := sql.Open("metrics-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	unregister, err := NewClient("test", BuildInfo{}, &Opts{Provider: limiter}).RegisterDBStatsCollector(db, "bans")
+	require.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "ab_test_bans_db_max_open_connections"))
+
+	_, err = NewClient("test", BuildInfo{}, &Opts{Provider: limiter}).RegisterDBStatsCollector(db, "bans")
+	assert.Error(t, err)
+
+	unregister()
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "ab_test_bans_db_max_open_connections"))
+}
+
+func TestObservableGaugeReplacesExisting(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+
+	provider.NewObservableGauge("ab_test_workers", "workers", func() []Observation {
+		return []Observation{{Value: 1}}
+	})
+	provider.NewObservableGauge("ab_test_workers", "workers", func() []Observation {
+		return []Observation{{Value: 2}}
+	})
+
+	expected := `
+# HELP ab_test_workers workers
+# TYPE ab_test_workers gauge
+ab_test_workers 2
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "ab_test_workers"))
+	assert.True(t, provider.Unregister("ab_test_workers"))
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "ab_test_workers"))
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exemplarTraceIDLabel is the exemplar label holding the trace ID, as expected by Grafana to link to the trace.
+const exemplarTraceIDLabel = "trace_id"
+
+// observeWithExemplar observes value, with the trace ID of the span of ctx as exemplar if the span is sampled
+// and the observer supports exemplars, i.e. a Prometheus histogram. The unsampled traces are not linked
+// since they are not exported.
+func observeWithExemplar(ctx context.Context, observer ObserverMetric, value float64) {
+	sc := trace.SpanContextFromContext(ctx)
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	if !ok || !sc.IsSampled() {
+		observer.Observe(value)
+		return
+	}
+	exemplarObserver.ObserveWithExemplar(value, prometheus.Labels{exemplarTraceIDLabel: sc.TraceID().String()})
+}
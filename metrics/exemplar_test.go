@@ -0,0 +1,111 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var exemplarSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: trace.FlagsSampled,
+})
+
+// histogramExemplars returns the trace IDs of the exemplars of the metric name.
+func histogramExemplars(t *testing.T, registry *prometheus.Registry, name string) []string {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var traceIDs []string
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				if exemplar := bucket.GetExemplar(); exemplar != nil {
+					traceIDs = append(traceIDs, exemplarTraceID(exemplar))
+				}
+			}
+		}
+	}
+	return traceIDs
+}
+
+func exemplarTraceID(exemplar *dto.Exemplar) string {
+	for _, label := range exemplar.GetLabel() {
+		if label.GetName() == exemplarTraceIDLabel {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func newExemplarClient() (*Client, *prometheus.Registry) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	return NewClient("test", BuildInfo{}, &Opts{Provider: provider}), registry
+}
+
+func TestDBCallExemplar(t *testing.T) {
+	client, registry := newExemplarClient()
+	dbMetrics := client.NewDBMetrics("bans")
+
+	dbMetrics.NewCallWithContext(trace.ContextWithSpanContext(context.Background(), exemplarSpanContext), "get_ban").CallEnded()
+	assert.Equal(t, []string{exemplarSpanContext.TraceID().String()}, histogramExemplars(t, registry, "ab_test_bans_db_latency_seconds"))
+}
+
+func TestDBCallExemplarNotSampled(t *testing.T) {
+	client, registry := newExemplarClient()
+	dbMetrics := client.NewDBMetrics("bans")
+
+	notSampled := exemplarSpanContext.WithTraceFlags(0)
+	dbMetrics.NewCallWithContext(trace.ContextWithSpanContext(context.Background(), notSampled), "get_ban").CallEnded()
+	dbMetrics.NewCall("get_ban").CallEnded()
+	assert.Empty(t, histogramExemplars(t, registry, "ab_test_bans_db_latency_seconds"))
+}
+
+func TestRestfulFilterExemplar(t *testing.T) {
+	client, registry := newExemplarClient()
+
+	ws := new(restful.WebService)
+	ws.Filter(client.RestfulFilter())
+	ws.Route(ws.GET("/bans").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteHeader(http.StatusOK)
+	}))
+	container := restful.NewContainer()
+	container.Add(ws)
+
+	req := httptest.NewRequest(http.MethodGet, "/bans", nil)
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), exemplarSpanContext))
+	container.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []string{exemplarSpanContext.TraceID().String()}, histogramExemplars(t, registry, "ab_service_request_http"))
+}
+
+func TestPrometheusProviderHandlerOpenMetrics(t *testing.T) {
+	client, _ := newExemplarClient()
+	client.NewDBMetrics("bans").NewCallWithContext(trace.ContextWithSpanContext(context.Background(), exemplarSpanContext), "get_ban").CallEnded()
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,application/openmetrics-text;version=0.0.1;q=0.75,text/plain;version=0.0.4;q=0.5")
+	rec := httptest.NewRecorder()
+	client.provider.(PrometheusProvider).Handler().ServeHTTP(rec, req)
+
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "application/openmetrics-text"))
+	assert.Contains(t, rec.Body.String(), `# {trace_id="`+exemplarSpanContext.TraceID().String()+`"}`)
+}
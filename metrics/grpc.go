@@ -0,0 +1,152 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/AccelByte/observability-go-sdk/internal/grpcwrap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor that records the request latency
+// with the default client set up by Initialize. Requests are not recorded before Initialize is called.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !defaultClient.initialized() {
+			return handler(ctx, req)
+		}
+		return defaultClient.UnaryServerInterceptor()(ctx, req, info, handler)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that records the stream latency
+// with the default client set up by Initialize. Streams are not recorded before Initialize is called.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !defaultClient.initialized() {
+			return handler(srv, ss)
+		}
+		return defaultClient.StreamServerInterceptor()(srv, ss, info, handler)
+	}
+}
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that records the call latency
+// with the default client set up by Initialize. Calls are not recorded before Initialize is called.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !defaultClient.initialized() {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		return defaultClient.UnaryClientInterceptor()(ctx, method, req, reply, cc, invoker, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that records the stream latency
+// with the default client set up by Initialize. Streams are not recorded before Initialize is called.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if !defaultClient.initialized() {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		return defaultClient.StreamClientInterceptor()(ctx, desc, cc, method, streamer, opts...)
+	}
+}
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor that records the request latency
+// labelled by service, method and status code.
+func (c *Client) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		dateStart := time.Now()
+		resp, err := handler(ctx, req)
+		observeGRPC(c.grpcServerMetrics, info.FullMethod, err, dateStart)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that records the stream latency
+// labelled by service, method and status code.
+func (c *Client) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		dateStart := time.Now()
+		err := handler(srv, ss)
+		observeGRPC(c.grpcServerMetrics, info.FullMethod, err, dateStart)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that records the call latency
+// labelled by service, method and status code.
+func (c *Client) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		dateStart := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		observeGRPC(c.grpcClientMetrics, method, err, dateStart)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that records the stream latency
+// labelled by service, method and status code. The stream ends when RecvMsg returns an error, io.EOF included,
+// or when it returns the response of a client-streaming RPC.
+func (c *Client) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		dateStart := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			observeGRPC(c.grpcClientMetrics, method, err, dateStart)
+			return nil, err
+		}
+		return &observedClientStream{
+			ClientStream:  stream,
+			serverStreams: desc.ServerStreams,
+			finish: func(err error) {
+				observeGRPC(c.grpcClientMetrics, method, err, dateStart)
+			},
+		}, nil
+	}
+}
+
+// observedClientStream calls finish once the stream has ended.
+type observedClientStream struct {
+	grpc.ClientStream
+	// serverStreams is false for the client-streaming RPCs, ending with their single response
+	serverStreams bool
+	once          sync.Once
+	finish        func(err error)
+}
+
+func (s *observedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil && !s.serverStreams {
+		s.once.Do(func() {
+			s.finish(nil)
+		})
+	}
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				s.finish(nil)
+				return
+			}
+			s.finish(err)
+		})
+	}
+	return err
+}
+
+func observeGRPC(metric ObserverVecMetric, fullMethod string, err error, dateStart time.Time) {
+	service, method := grpcwrap.SplitFullMethod(fullMethod)
+	metric.With(map[string]string{
+		labelService: service,
+		labelMethod:  method,
+		labelCode:    status.Code(err).String(),
+	}).Observe(time.Since(dateStart).Seconds())
+}
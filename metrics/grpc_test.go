@@ -0,0 +1,72 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider})
+
+	interceptor := client.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/accelbyte.bans.v1.Bans/GetBan"}
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "ban not found")
+	})
+	assert.Error(t, err)
+
+	histogram := client.grpcServerMetrics.(histogramVec)
+	assert.Equal(t, 1, testutil.CollectAndCount(histogram.HistogramVec))
+	_, err = histogram.GetMetricWith(prometheus.Labels{
+		labelService: "accelbyte.bans.v1.Bans",
+		labelMethod:  "GetBan",
+		labelCode:    codes.NotFound.String(),
+	})
+	assert.NoError(t, err)
+}
+
+// responseClientStream is a client stream receiving a single response, like a client-streaming RPC.
+type responseClientStream struct {
+	grpc.ClientStream
+}
+
+func (responseClientStream) RecvMsg(interface{}) error {
+	return nil
+}
+
+func TestStreamClientInterceptorClientStreaming(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider})
+
+	desc := &grpc.StreamDesc{ClientStreams: true}
+	stream, err := client.StreamClientInterceptor()(context.Background(), desc, nil, "/accelbyte.bans.v1.Bans/ImportBans",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return responseClientStream{}, nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, stream.RecvMsg(nil))
+
+	histogram := client.grpcClientMetrics.(histogramVec)
+	assert.Equal(t, 1, testutil.CollectAndCount(histogram.HistogramVec))
+	_, err = histogram.GetMetricWith(prometheus.Labels{
+		labelService: "accelbyte.bans.v1.Bans",
+		labelMethod:  "ImportBans",
+		labelCode:    codes.OK.String(),
+	})
+	assert.NoError(t, err)
+}
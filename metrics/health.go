@@ -0,0 +1,259 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// HealthStatusUp is the status of a passing check, and of a report whose checks all pass.
+	HealthStatusUp = "up"
+	// HealthStatusDown is the status of a failing or timed out check, and of a report with a failing check.
+	HealthStatusDown = "down"
+
+	metricsNameHealthCheckStatus  = "health_check_status"
+	metricsNameHealthCheckLatency = "health_check_latency_seconds"
+	labelCheck                    = "check"
+
+	defaultHealthCheckTimeout       = 5 * time.Second
+	defaultHealthCheckCacheDuration = 5 * time.Second
+)
+
+// HealthCheck checks a dependency of the service, e.g. a database, and returns an error if it is unavailable.
+// It should return when ctx is done.
+type HealthCheck func(ctx context.Context) error
+
+// HealthCheckOpts represents the options of a health check.
+type HealthCheckOpts struct {
+	// Timeout fails the check if it takes longer, default is 5 seconds.
+	Timeout time.Duration
+	// CacheDuration is how long the result is reused before the check runs again, so that frequent probes
+	// do not hammer the dependency. Default is 5 seconds, a negative value disables the caching.
+	CacheDuration time.Duration
+	// Liveness also reports the check on the health route, the liveness probe, instead of only on the
+	// readiness route. Only the checks whose failure requires a restart of the service should set it.
+	Liveness bool
+}
+
+// HealthReport is the JSON body of the health and readiness routes.
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks"`
+}
+
+// HealthCheckResult is the result of a check in a HealthReport.
+type HealthCheckResult struct {
+	Status         string    `json:"status"`
+	Error          string    `json:"error,omitempty"`
+	LatencySeconds float64   `json:"latency_seconds"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// HealthChecks is a registry of named checks served by the HealthRoute and ReadinessRoute of the metrics
+// web service. The status (1 up, 0 down) and latency of each check run are exported as the
+// ab.<service>_health_check_status and ab.<service>_health_check_latency_seconds gauges, labelled by check.
+type HealthChecks struct {
+	status  GaugeVecMetric
+	latency GaugeVecMetric
+
+	mu     sync.RWMutex
+	checks map[string]*healthCheck
+}
+
+// NewHealthChecks returns a new health check registry exporting its metrics with metricsProvider
+// and the default client service name.
+func NewHealthChecks(metricsProvider Provider) *HealthChecks {
+	return newHealthChecks(metricsProvider, defaultClient.serviceName)
+}
+
+// NewHealthChecks returns a new health check registry exporting its metrics with the client provider and service name.
+func (c *Client) NewHealthChecks() *HealthChecks {
+	return newHealthChecks(c.provider, c.serviceName)
+}
+
+func newHealthChecks(metricsProvider Provider, serviceName string) *HealthChecks {
+	return &HealthChecks{
+		status: metricsProvider.NewGauge(generateMetricsName(serviceName, metricsNameHealthCheckStatus),
+			"Status of the last run of the health check, 1 if up and 0 if down", labelCheck),
+		latency: metricsProvider.NewGauge(generateMetricsName(serviceName, metricsNameHealthCheckLatency),
+			"Latency of the last run of the health check in seconds", labelCheck),
+		checks: map[string]*healthCheck{},
+	}
+}
+
+// Add adds check under name, replacing the check previously added with the same name.
+// opts may be nil to use the default options.
+func (h *HealthChecks) Add(name string, check HealthCheck, opts *HealthCheckOpts) *HealthChecks {
+	checkOpts := HealthCheckOpts{}
+	if opts != nil {
+		checkOpts = *opts
+	}
+	if checkOpts.Timeout <= 0 {
+		checkOpts.Timeout = defaultHealthCheckTimeout
+	}
+	if checkOpts.CacheDuration == 0 {
+		checkOpts.CacheDuration = defaultHealthCheckCacheDuration
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = &healthCheck{name: name, check: check, opts: checkOpts, checks: h}
+	return h
+}
+
+// Remove removes the check added under name.
+func (h *HealthChecks) Remove(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.checks, name)
+}
+
+// Readiness runs every check concurrently, or reuses their cached results, and reports them.
+func (h *HealthChecks) Readiness(ctx context.Context) HealthReport {
+	return h.report(ctx, false)
+}
+
+// Liveness runs the checks added with HealthCheckOpts.Liveness concurrently, or reuses their cached results,
+// and reports them. The report is up if there is no such check.
+func (h *HealthChecks) Liveness(ctx context.Context) HealthReport {
+	return h.report(ctx, true)
+}
+
+func (h *HealthChecks) report(ctx context.Context, liveness bool) HealthReport {
+	h.mu.RLock()
+	checks := make([]*healthCheck, 0, len(h.checks))
+	for _, check := range h.checks {
+		if check.opts.Liveness || !liveness {
+			checks = append(checks, check)
+		}
+	}
+	h.mu.RUnlock()
+	sort.Slice(checks, func(i, j int) bool { return checks[i].name < checks[j].name })
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *healthCheck) {
+			defer wg.Done()
+			results[i] = check.run(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: HealthStatusUp, Checks: make(map[string]HealthCheckResult, len(checks))}
+	for i, check := range checks {
+		report.Checks[check.name] = results[i]
+		if results[i].Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+		}
+	}
+	return report
+}
+
+// LivenessHandler returns the http.Handler serving the Liveness report as JSON,
+// with the status 200 if it is up or else 503.
+func (h *HealthChecks) LivenessHandler() http.Handler {
+	return h.handler(true)
+}
+
+// ReadinessHandler returns the http.Handler serving the Readiness report as JSON,
+// with the status 200 if it is up or else 503.
+func (h *HealthChecks) ReadinessHandler() http.Handler {
+	return h.handler(false)
+}
+
+func (h *HealthChecks) handler(liveness bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.report(r.Context(), liveness)
+		code := http.StatusOK
+		if report.Status != HealthStatusUp {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// healthCheck represents a check added to HealthChecks with its cached result.
+type healthCheck struct {
+	name   string
+	check  HealthCheck
+	opts   HealthCheckOpts
+	checks *HealthChecks
+
+	// mu is held during the run so that the concurrent reports share the same run
+	mu     sync.Mutex
+	result *HealthCheckResult
+}
+
+func (c *healthCheck) run(ctx context.Context) HealthCheckResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.result != nil && time.Since(c.result.CheckedAt) < c.opts.CacheDuration {
+		return *c.result
+	}
+
+	// the result is cached for the next probes, so the check must not fail because this probe went away
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	// the check runs in its own goroutine to time out even if it ignores ctx
+	done := make(chan error, 1)
+	go func() {
+		done <- c.check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s: %w", c.opts.Timeout, ctx.Err())
+	}
+	latency := time.Since(start)
+
+	result := HealthCheckResult{Status: HealthStatusUp, LatencySeconds: latency.Seconds(), CheckedAt: start}
+	status := 1.0
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+		status = 0
+	}
+	labels := map[string]string{labelCheck: c.name}
+	c.checks.status.With(labels).Set(status)
+	c.checks.latency.With(labels).Set(latency.Seconds())
+
+	c.result = &result
+	return result
+}
+
+// DBPingCheck returns a HealthCheck pinging db.
+func DBPingCheck(db *sql.DB) HealthCheck {
+	return db.PingContext
+}
+
+// DialCheck returns a HealthCheck connecting to address on the named network, e.g. "tcp" and the
+// OpenTelemetry collector endpoint, to check that it is reachable.
+func DialCheck(network, address string) HealthCheck {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHealthChecks() (*HealthChecks, *prometheus.Registry) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	return NewClient("test", BuildInfo{}, &Opts{Provider: provider}).NewHealthChecks(), registry
+}
+
+func TestHealthChecksReport(t *testing.T) {
+	checks, registry := newTestHealthChecks()
+	checks.
+		Add("db", func(ctx context.Context) error { return nil }, &HealthCheckOpts{Liveness: true}).
+		Add("cache", func(ctx context.Context) error { return errors.New("connection refused") }, nil)
+
+	liveness := checks.Liveness(context.Background())
+	assert.Equal(t, HealthStatusUp, liveness.Status)
+	assert.Len(t, liveness.Checks, 1)
+
+	readiness := checks.Readiness(context.Background())
+	assert.Equal(t, HealthStatusDown, readiness.Status)
+	assert.Equal(t, HealthStatusUp, readiness.Checks["db"].Status)
+	assert.Equal(t, HealthStatusDown, readiness.Checks["cache"].Status)
+	assert.Equal(t, "connection refused", readiness.Checks["cache"].Error)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(checks.status.(gaugeVec).WithLabelValues("db")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(checks.status.(gaugeVec).WithLabelValues("cache")))
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "ab_test_health_check_latency_seconds"))
+}
+
+func TestHealthCheckCacheAndTimeout(t *testing.T) {
+	checks, _ := newTestHealthChecks()
+	var runs int32
+	checks.Add("slow", func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		time.Sleep(time.Second)
+		return nil
+	}, &HealthCheckOpts{Timeout: 10 * time.Millisecond, CacheDuration: time.Minute})
+
+	first := checks.Readiness(context.Background())
+	second := checks.Readiness(context.Background())
+
+	assert.Equal(t, HealthStatusDown, first.Checks["slow"].Status)
+	assert.Contains(t, first.Checks["slow"].Error, "timed out")
+	assert.Less(t, first.Checks["slow"].LatencySeconds, 0.5)
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&runs))
+}
+
+func TestHealthCheckIgnoresCancelledProbe(t *testing.T) {
+	checks, _ := newTestHealthChecks()
+	checks.Add("db", func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return ctx.Err()
+	}, &HealthCheckOpts{CacheDuration: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, HealthStatusUp, checks.Readiness(ctx).Status)
+	assert.Equal(t, HealthStatusUp, checks.Readiness(context.Background()).Status)
+}
+
+func TestDialCheck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	assert.NoError(t, DialCheck("tcp", address)(context.Background()))
+	require.NoError(t, listener.Close())
+	assert.Error(t, DialCheck("tcp", address)(context.Background()))
+}
+
+func TestHealthRoutes(t *testing.T) {
+	checks, _ := newTestHealthChecks()
+	checks.Add("collector", func(ctx context.Context) error { return errors.New("unreachable") }, nil)
+
+	container := restful.NewContainer()
+	container.Add(NewWebService("/test").HealthRoute(checks).ReadinessRoute(checks).WebService())
+
+	rec := httptest.NewRecorder()
+	container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test/admin/internal/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	container.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test/admin/internal/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var report HealthReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, HealthStatusDown, report.Status)
+	assert.Equal(t, "unreachable", report.Checks["collector"].Error)
+}
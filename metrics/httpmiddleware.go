@@ -0,0 +1,89 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AccelByte/observability-go-sdk/internal/httpwrap"
+)
+
+// defaultHTTPRoute is the path label of the requests recorded without a RouteExtractor.
+const defaultHTTPRoute = "/*"
+
+// RouteExtractor returns the route template of the request, i.e. /bans/{banId}, or an empty string
+// when the request did not match any route. Returning templates instead of raw paths keeps the
+// path label cardinality bounded.
+type RouteExtractor func(r *http.Request) string
+
+// ServeMuxRouteExtractor returns a RouteExtractor that reads the matching pattern from mux.
+func ServeMuxRouteExtractor(mux *http.ServeMux) RouteExtractor {
+	return func(r *http.Request) string {
+		return httpwrap.ServeMuxRoute(mux, r)
+	}
+}
+
+// HTTPMiddlewareOpts represents the net/http middleware configuration options.
+type HTTPMiddlewareOpts struct {
+	// RouteExtractor is called after the request is served, so routers that resolve the route
+	// while serving (i.e. chi.RouteContext(r.Context()).RoutePattern()) are supported.
+	// Requests without a route are not recorded, like the unmatched requests in RestfulFilter.
+	// Default records every request with the fixed route "/*", so that the path label stays bounded.
+	RouteExtractor RouteExtractor
+
+	// NamespaceExtractor returns the namespace label of the request, default is an empty namespace.
+	NamespaceExtractor func(r *http.Request) string
+}
+
+// HTTPMiddleware returns a net/http middleware that records the HTTP metrics with the default
+// client set up by Initialize. It is the net/http equivalent of RestfulFilter.
+// Requests are not recorded before Initialize is called.
+func HTTPMiddleware(opts HTTPMiddlewareOpts) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !defaultClient.initialized() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			defaultClient.serveHTTP(opts, next, w, r)
+		})
+	}
+}
+
+// HTTPMiddleware returns a net/http middleware that records the HTTP metrics with the client provider.
+func (c *Client) HTTPMiddleware(opts HTTPMiddlewareOpts) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.serveHTTP(opts, next, w, r)
+		})
+	}
+}
+
+func (c *Client) serveHTTP(opts HTTPMiddlewareOpts, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	dateStart := time.Now()
+	recorder, rw := httpwrap.NewStatusRecorder(w)
+	next.ServeHTTP(rw, r)
+
+	route := defaultHTTPRoute
+	if opts.RouteExtractor != nil {
+		if route = opts.RouteExtractor(r); route == "" {
+			return
+		}
+	}
+
+	var namespace string
+	if opts.NamespaceExtractor != nil {
+		namespace = opts.NamespaceExtractor(r)
+	}
+
+	observeWithExemplar(r.Context(), c.httpMetrics.With(map[string]string{
+		labelNamespace:    namespace,
+		labelPath:         route,
+		labelMethod:       r.Method,
+		labelResponseCode: strconv.Itoa(recorder.StatusCode),
+	}), time.Since(dateStart).Seconds())
+}
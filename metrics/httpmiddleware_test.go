@@ -0,0 +1,88 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/bans/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := client.HTTPMiddleware(HTTPMiddlewareOpts{
+		RouteExtractor: ServeMuxRouteExtractor(mux),
+		NamespaceExtractor: func(r *http.Request) string {
+			return r.Header.Get("Namespace")
+		},
+	})(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/bans/1", nil)
+	req.Header.Set("Namespace", "accelbyte")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	families, err := registry.Gather()
+	assert.NoError(t, err)
+
+	var requests []*dto.Metric
+	for _, family := range families {
+		if family.GetName() == "ab_service_request_http" {
+			requests = family.GetMetric()
+		}
+	}
+	if assert.Len(t, requests, 1) {
+		labels := map[string]string{}
+		for _, label := range requests[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		assert.Equal(t, map[string]string{
+			"namespace":     "accelbyte",
+			"path":          "/bans/",
+			"method":        http.MethodGet,
+			"response_code": "404",
+		}, labels)
+		assert.Equal(t, uint64(1), requests[0].GetHistogram().GetSampleCount())
+	}
+}
+
+func TestHTTPMiddlewareDefaultRoute(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider})
+
+	handler := client.HTTPMiddleware(HTTPMiddlewareOpts{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, isFlusher := w.(http.Flusher)
+		_, isHijacker := w.(http.Hijacker)
+		assert.True(t, isFlusher)
+		assert.False(t, isHijacker)
+		assert.NoError(t, http.NewResponseController(w).Flush())
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bans/1", nil))
+	assert.True(t, rec.Flushed)
+
+	histogram := client.httpMetrics.(histogramVec)
+	_, err := histogram.GetMetricWith(prometheus.Labels{
+		labelNamespace:    "",
+		labelPath:         defaultHTTPRoute,
+		labelMethod:       http.MethodGet,
+		labelResponseCode: "200",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(histogram.HistogramVec))
+}
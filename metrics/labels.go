@@ -0,0 +1,120 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNameLabelErrors = "ab_metrics_label_errors_total"
+
+// LabelError is reported to the error handler of the provider when With is called with labels
+// that do not match the declared ones. The series is still recorded, with the missing labels
+// set to an empty value and the unknown labels dropped.
+type LabelError struct {
+	Metric  string
+	Missing []string
+	Unknown []string
+}
+
+func (e *LabelError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing labels %s", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Unknown) > 0 {
+		problems = append(problems, fmt.Sprintf("unknown labels %s", strings.Join(e.Unknown, ", ")))
+	}
+	return fmt.Sprintf("metric %s: %s", e.Metric, strings.Join(problems, " and "))
+}
+
+// labelValidator reports the label errors of the metrics of one provider, through its error handler
+// and the ab_metrics_label_errors_total counter. Every error is counted, but the error handler is called
+// once per metric and shape of the wrong labels, so that a bad call in a hot path does not flood the logs.
+type labelValidator struct {
+	registerer   prometheus.Registerer
+	errorHandler func(err error)
+
+	once   sync.Once
+	errors *prometheus.CounterVec
+	// reported holds the messages of the errors already passed to the error handler
+	reported sync.Map
+}
+
+func newLabelValidator(registerer prometheus.Registerer, errorHandler func(err error)) *labelValidator {
+	return &labelValidator{registerer: registerer, errorHandler: errorHandler}
+}
+
+func (v *labelValidator) report(err *LabelError) {
+	// the counter is registered on the first error only, to not expose it for the services without errors
+	v.once.Do(func() {
+		v.errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: metricsNameLabelErrors,
+			Help: "Number of metric updates with labels not matching the declared ones",
+		}, []string{labelMetric})
+		if err := v.registerer.Register(v.errors); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if errors.As(err, &alreadyRegistered) {
+				if existing, ok := alreadyRegistered.ExistingCollector.(*prometheus.CounterVec); ok {
+					v.errors = existing
+				}
+			}
+		}
+	})
+
+	v.errors.WithLabelValues(err.Metric).Inc()
+	if _, loaded := v.reported.LoadOrStore(err.Error(), struct{}{}); !loaded {
+		v.errorHandler(err)
+	}
+}
+
+// labelSet represents the declared labels of a metric.
+type labelSet struct {
+	metric    string
+	names     []string
+	validator *labelValidator
+}
+
+// fix returns labels if they match the declared labels, or else the labels with the missing ones set
+// to an empty value and without the unknown ones, reporting the error to the validator.
+func (s labelSet) fix(labels map[string]string) map[string]string {
+	matching := len(labels) == len(s.names)
+	for _, name := range s.names {
+		if !matching {
+			break
+		}
+		_, matching = labels[name]
+	}
+	if matching {
+		return labels
+	}
+
+	labelErr := &LabelError{Metric: s.metric}
+	fixed := make(map[string]string, len(s.names))
+	for _, name := range s.names {
+		value, ok := labels[name]
+		if !ok {
+			labelErr.Missing = append(labelErr.Missing, name)
+		}
+		fixed[name] = value
+	}
+	for name := range labels {
+		if _, ok := fixed[name]; !ok {
+			labelErr.Unknown = append(labelErr.Unknown, name)
+		}
+	}
+	sort.Strings(labelErr.Unknown)
+
+	if s.validator != nil {
+		s.validator.report(labelErr)
+	}
+	return fixed
+}
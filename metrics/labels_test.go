@@ -0,0 +1,81 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusProviderFixesLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	var reported []error
+	provider := NewPrometheusProvider(PrometheusProviderOpts{
+		Registerer:   registry,
+		Gatherer:     registry,
+		ErrorHandler: func(err error) { reported = append(reported, err) },
+	})
+
+	counter := provider.NewCounter("requests", "requests", "method", "code")
+	assert.NotPanics(t, func() {
+		counter.With(map[string]string{"method": "GET", "user": "alice"}).Inc()
+		counter.With(map[string]string{"method": "POST", "user": "bob"}).Inc()
+		counter.With(map[string]string{"method": "GET", "code": "200"}).Inc()
+	})
+
+	vec := counter.(counterVec)
+	assert.Equal(t, 1.0, testutil.ToFloat64(vec.WithLabelValues("GET", "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(vec.WithLabelValues("POST", "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(vec.WithLabelValues("GET", "200")))
+
+	require.Len(t, reported, 1)
+	labelErr, ok := reported[0].(*LabelError)
+	require.True(t, ok)
+	assert.Equal(t, &LabelError{Metric: "requests", Missing: []string{"code"}, Unknown: []string{"user"}}, labelErr)
+	assert.Equal(t, "metric requests: missing labels code and unknown labels user", labelErr.Error())
+
+	errorsCounter, err := registry.Gather()
+	require.NoError(t, err)
+	var found bool
+	for _, family := range errorsCounter {
+		if family.GetName() == metricsNameLabelErrors {
+			found = true
+			assert.Equal(t, 2.0, family.GetMetric()[0].GetCounter().GetValue())
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestDBCallUnknownLabel(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{
+		Registerer:   registry,
+		Gatherer:     registry,
+		ErrorHandler: func(error) {},
+	})
+	dbMetrics := newDBMetrics(provider, "test", nil, "bans")
+
+	assert.NotPanics(t, func() {
+		dbMetrics.NewCall("get_ban").WithLabel(map[string]string{"tenant": "accelbyte"}).CallEnded()
+	})
+	assert.Equal(t, 1, testutil.CollectAndCount(dbMetrics.latencyMetrics.(histogramVec).HistogramVec))
+}
+
+func TestLabelValidatorExistingCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricsNameLabelErrors,
+		Help: "Number of metric updates with labels not matching the declared ones",
+	}, []string{labelMetric})))
+	validator := newLabelValidator(registry, func(error) {})
+
+	assert.NotPanics(t, func() {
+		validator.report(&LabelError{Metric: "requests", Missing: []string{"code"}})
+	})
+}
@@ -6,16 +6,14 @@ package metrics
 
 import (
 	"fmt"
-	"runtime/metrics"
+	"strings"
+	"time"
 )
 
 var (
-	DefaultProvider        Provider = NewPrometheusProvider(PrometheusProviderOpts{})
-	serviceName            string
-	namespacePathParameter string
-	enableRuntimeMetrics   bool
+	DefaultProvider Provider = NewPrometheusProvider(PrometheusProviderOpts{})
 
-	httpMetrics ObserverVecMetric
+	defaultClient = &Client{}
 )
 
 // CounterVecMetric represents a vector counter metric containing a variation
@@ -63,79 +61,135 @@ type Provider interface {
 	NewGauge(name, help string, labels ...string) GaugeVecMetric
 	NewHistogram(name, help string, buckets []float64, labels ...string) ObserverVecMetric
 	NewSummary(name, help string, labels ...string) ObserverVecMetric
-	initBuildInfo(info BuildInfo)
 }
 
-type BuildInfo struct {
-	RevisionID,
-	BuildDate,
-	Version,
-	GitHash,
-	RoleSeedingVersion string
+// Observation represents one value reported by an observable metric callback.
+type Observation struct {
+	Value  float64
+	Labels map[string]string
 }
 
-type Opts struct {
-	NamespacePath        string
-	EnableRuntimeMetrics bool
+// ObservableProvider represents a metric provider able to read the metric values at collection time,
+// i.e. at scrape time for Prometheus, instead of having them pushed by the caller.
+type ObservableProvider interface {
+	Provider
+	NewObservableGauge(name, help string, callback func() []Observation, labels ...string)
+	NewObservableCounter(name, help string, callback func() []Observation, labels ...string)
+}
 
-	CustomHTTPMetrics *ObserverVecMetric
+// NativeHistogramOpts represents the native (sparse) histogram configuration options. Native histograms have
+// exponential buckets covering the whole range of values, so no bucket boundary has to be picked.
+// They require a Prometheus server with the native histograms feature enabled.
+type NativeHistogramOpts struct {
+	// BucketFactor is the maximum growth factor between two consecutive buckets, default is 1.1.
+	// The smaller the factor, the more precise and costly the histogram.
+	BucketFactor float64
+	// MaxBucketNumber limits the number of buckets of each histogram, the resolution is reduced once it is
+	// exceeded. Default is 160.
+	MaxBucketNumber uint32
+	// ZeroThreshold is the absolute value below which the observations are counted in the zero bucket,
+	// default is prometheus.DefNativeHistogramZeroThreshold. Negative means only the zeros are counted.
+	ZeroThreshold float64
+	// MinResetDuration resets the histogram instead of reducing its resolution when MaxBucketNumber
+	// is exceeded, if the histogram was not reset since MinResetDuration. Default is 0 = never reset.
+	MinResetDuration time.Duration
+	// ClassicBuckets also exports the classic buckets, for the dashboards and scrapers not supporting
+	// native histograms yet. The buckets are the ones passed to the provider, or prometheus.DefBuckets.
+	ClassicBuckets bool
 }
 
-func Initialize(s string, buildInfo BuildInfo, option *Opts) {
-	serviceName = s
+// NativeHistogramProvider represents a metric provider supporting native histograms, i.e. Prometheus.
+type NativeHistogramProvider interface {
+	Provider
+	NewNativeHistogram(name, help string, buckets []float64, opts NativeHistogramOpts, labels ...string) ObserverVecMetric
+}
 
-	initializeDefaultOption()
+// MetricOpts represents the definition options of a metric. The zero value defines the same metric
+// as the Provider methods without options.
+type MetricOpts struct {
+	Namespace   string            // first part of the metric name, e.g. ab
+	Subsystem   string            // second part of the metric name, e.g. the service name
+	Unit        string            // suffix of the metric name unless already present, e.g. seconds
+	ConstLabels map[string]string // labels with a fixed value added to every series
 
-	if option != nil {
-		overrideDefaultOption(option)
-	}
+	Buckets         []float64            // histogram buckets, default is prometheus.DefBuckets
+	NativeHistogram *NativeHistogramOpts // makes the histogram native with a NativeHistogramProvider
 
-	DefaultProvider.initBuildInfo(buildInfo)
-
-	if enableRuntimeMetrics {
-		startRuntimeMetrics()
-	}
+	Objectives map[float64]float64 // summary quantiles with their absolute error, default is DefaultObjectives
+	MaxAge     time.Duration       // how long the summary observations count in the quantiles, default is 10 minutes
+	AgeBuckets uint32              // number of buckets rotated over MaxAge, default is 5
 }
 
-func initializeDefaultOption() {
-	namespacePathParameter = defaultNamespacePathParameter
-	enableRuntimeMetrics = true
-
-	httpMetrics = HistogramVecWithBuckets(
-		generateMetricsName(genericServiceName, metricsNameHTTP),
-		"HTTP request in histogram",
-		[]float64{0.001, 0.01, 0.1, 0.5, 1, 1.5, 2, 3, 4, 5, 7.5, 10, 15, 20},
-		[]string{labelNamespace, labelPath, labelMethod, labelResponseCode},
-	)
-}
+// DefaultObjectives are the quantiles of the summaries defined without objectives: the median, p90 and p99.
+var DefaultObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
 
-func overrideDefaultOption(option *Opts) {
-	if option.NamespacePath != "" {
-		namespacePathParameter = option.NamespacePath
+// FullName returns the name of the metric called name: the namespace, subsystem, name and unit joined with underscores.
+func (o MetricOpts) FullName(name string) string {
+	if o.Unit != "" && !strings.HasSuffix(name, "_"+o.Unit) {
+		name += "_" + o.Unit
 	}
-	if !option.EnableRuntimeMetrics {
-		enableRuntimeMetrics = false
-	}
-	if option.CustomHTTPMetrics != nil {
-		httpMetrics = *option.CustomHTTPMetrics
+	parts := make([]string, 0, 3)
+	for _, part := range []string{o.Namespace, o.Subsystem, name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
 	}
+	return strings.Join(parts, "_")
 }
 
-func startRuntimeMetrics() {
-	runtimeMetricsGaugeMap = make(map[string]GaugeMetric)
-	runtimeMetricsHistogram = make(map[string]ObserverMetric)
+// wrapperProvider represents a provider wrapping another one, e.g. CardinalityLimiter.
+type wrapperProvider interface {
+	Unwrap() Provider
+}
 
-	descs := metrics.All()
-	for _, desc := range descs {
-		switch desc.Kind {
-		case metrics.KindUint64, metrics.KindFloat64:
-			runtimeMetricsGaugeMap[desc.Name] = Gauge(generateMetricsName(genericServiceName, desc.Name), desc.Description)
-		case metrics.KindFloat64Histogram:
-			runtimeMetricsHistogram[desc.Name] = Histogram(generateMetricsName(genericServiceName, desc.Name), desc.Description)
+// unwrapProvider returns the innermost provider wrapped by p, or p if it does not wrap another provider.
+func unwrapProvider(p Provider) Provider {
+	for {
+		wrapper, ok := p.(wrapperProvider)
+		if !ok {
+			return p
 		}
+		p = wrapper.Unwrap()
 	}
+}
 
-	go sendRuntimeMetrics()
+// OptsProvider represents a metric provider supporting MetricOpts. Every provider of this module implements it.
+type OptsProvider interface {
+	Provider
+	NewCounterWithOpts(name, help string, opts MetricOpts, labels ...string) CounterVecMetric
+	NewGaugeWithOpts(name, help string, opts MetricOpts, labels ...string) GaugeVecMetric
+	NewHistogramWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric
+	NewSummaryWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric
+}
+
+type BuildInfo struct {
+	RevisionID,
+	BuildDate,
+	Version,
+	GitHash,
+	RoleSeedingVersion string
+}
+
+type Opts struct {
+	Provider             Provider // default is DefaultProvider
+	NamespacePath        string
+	EnableRuntimeMetrics bool
+	// NativeHistograms makes the HTTP, gRPC and DB latency histograms native if the provider is
+	// a NativeHistogramProvider, default is classic histograms with fixed buckets.
+	NativeHistograms *NativeHistogramOpts
+
+	CustomHTTPMetrics *ObserverVecMetric
+}
+
+// Initialize initializes the default client used by the package level functions such as RestfulFilter and NewDBMetrics.
+// Use NewClient instead to run several independent instances in the same binary.
+// The runtime metrics of the previous default client are stopped.
+func Initialize(s string, buildInfo BuildInfo, option *Opts) {
+	if option != nil && option.Provider != nil {
+		SetProvider(option.Provider)
+	}
+	defaultClient.StopRuntimeMetrics()
+	defaultClient = NewClient(s, buildInfo, option)
 }
 
 func generateMetricsName(prefix, metricsName string) string {
@@ -231,3 +285,40 @@ func Summary(name string, help string) ObserverMetric {
 func SummaryVec(name string, help string, labels []string) ObserverVecMetric {
 	return DefaultProvider.NewSummary(name, help, labels...)
 }
+
+// CounterVecWithOpts creates a counter vector metric defined by opts with default provider.
+// Only the name of opts is honored if the default provider is not an OptsProvider.
+func CounterVecWithOpts(name, help string, opts MetricOpts, labels []string) CounterVecMetric {
+	if p, ok := DefaultProvider.(OptsProvider); ok {
+		return p.NewCounterWithOpts(name, help, opts, labels...)
+	}
+	return DefaultProvider.NewCounter(opts.FullName(name), help, labels...)
+}
+
+// GaugeVecWithOpts creates a gauge vector metric defined by opts with default provider.
+// Only the name of opts is honored if the default provider is not an OptsProvider.
+func GaugeVecWithOpts(name, help string, opts MetricOpts, labels []string) GaugeVecMetric {
+	if p, ok := DefaultProvider.(OptsProvider); ok {
+		return p.NewGaugeWithOpts(name, help, opts, labels...)
+	}
+	return DefaultProvider.NewGauge(opts.FullName(name), help, labels...)
+}
+
+// HistogramVecWithOpts creates a histogram vector metric defined by opts with default provider.
+// Only the name and buckets of opts are honored if the default provider is not an OptsProvider.
+func HistogramVecWithOpts(name, help string, opts MetricOpts, labels []string) ObserverVecMetric {
+	if p, ok := DefaultProvider.(OptsProvider); ok {
+		return p.NewHistogramWithOpts(name, help, opts, labels...)
+	}
+	return DefaultProvider.NewHistogram(opts.FullName(name), help, opts.Buckets, labels...)
+}
+
+// SummaryVecWithOpts creates a summary vector metric defined by opts with default provider, e.g. with
+// the quantiles needed by the dashboards as Objectives.
+// Only the name of opts is honored if the default provider is not an OptsProvider.
+func SummaryVecWithOpts(name, help string, opts MetricOpts, labels []string) ObserverVecMetric {
+	if p, ok := DefaultProvider.(OptsProvider); ok {
+		return p.NewSummaryWithOpts(name, help, opts, labels...)
+	}
+	return DefaultProvider.NewSummary(opts.FullName(name), help, labels...)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metricstest
+
+import (
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertCounter asserts that the counter with the given name and labels has the expected value.
+func (p *Provider) AssertCounter(t assert.TestingT, name string, labels map[string]string, want float64) bool {
+	return p.assertValue(t, KindCounter, name, labels, want)
+}
+
+// AssertGauge asserts that the gauge with the given name and labels has the expected value.
+func (p *Provider) AssertGauge(t assert.TestingT, name string, labels map[string]string, want float64) bool {
+	return p.assertValue(t, KindGauge, name, labels, want)
+}
+
+// AssertObservationCount asserts that the histogram or summary with the given name and labels
+// has recorded the expected number of observations.
+func (p *Provider) AssertObservationCount(t assert.TestingT, name string, labels map[string]string, want int) bool {
+	if !p.assertKind(t, name, KindHistogram, KindSummary) {
+		return false
+	}
+	return assert.Len(t, p.ObservationsOf(name, labels), want, "observations of %q with labels %v", name, labels)
+}
+
+func (p *Provider) assertValue(t assert.TestingT, kind Kind, name string, labels map[string]string, want float64) bool {
+	if !p.assertKind(t, name, kind) {
+		return false
+	}
+	got, ok := p.Value(name, labels)
+	if !ok {
+		return assert.Fail(t, "no value recorded", "%s %q with labels %v, recorded label sets: %v",
+			kind, name, labels, p.LabelSets(name))
+	}
+	return assert.Equal(t, want, got, "%s %q with labels %v", kind, name, labels)
+}
+
+func (p *Provider) assertKind(t assert.TestingT, name string, kinds ...Kind) bool {
+	p.mu.RLock()
+	m, ok := p.metrics[name]
+	p.mu.RUnlock()
+	if !ok {
+		return assert.Fail(t, "metric not found", "%q is not registered, registered metrics: %v", name, p.Names())
+	}
+	for _, kind := range kinds {
+		if m.kind == kind {
+			return true
+		}
+	}
+	return assert.Fail(t, "unexpected metric kind", "%q is a %s, expected %v", name, m.kind, kinds)
+}
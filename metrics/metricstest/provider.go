@@ -0,0 +1,412 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+// Package metricstest provides an in-memory metrics.Provider to unit test instrumentation
+// without a real Prometheus registry.
+package metricstest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AccelByte/observability-go-sdk/metrics"
+)
+
+// Kind is the type of the recorded metric.
+type Kind string
+
+const (
+	KindCounter   Kind = "counter"
+	KindGauge     Kind = "gauge"
+	KindHistogram Kind = "histogram"
+	KindSummary   Kind = "summary"
+)
+
+// Provider is an in-memory metrics.Provider that records every counter increment,
+// gauge set and histogram/summary observation with its labels.
+// Unlike the Prometheus provider, which reports and fixes them, it panics when With is called with labels
+// that do not match the declared ones, so that the tests catch the mistakes.
+type Provider struct {
+	mu      sync.RWMutex
+	metrics map[string]*metric
+}
+
+type metric struct {
+	name    string
+	help    string
+	kind    Kind
+	labels  []string
+	buckets []float64
+	opts    metrics.MetricOpts
+	series  map[string]*series
+
+	// callback reads the values of the observable metrics when they are looked up
+	callback func() []metrics.Observation
+}
+
+type series struct {
+	labels       map[string]string
+	value        float64
+	observations []float64
+}
+
+// NewProvider creates a new empty in-memory provider.
+func NewProvider() *Provider {
+	return &Provider{metrics: map[string]*metric{}}
+}
+
+// NewCounter creates a new in-memory counter vector metric.
+func (p *Provider) NewCounter(name, help string, labels ...string) metrics.CounterVecMetric {
+	return p.NewCounterWithOpts(name, help, metrics.MetricOpts{}, labels...)
+}
+
+// NewCounterWithOpts creates a new in-memory counter vector metric defined by opts.
+func (p *Provider) NewCounterWithOpts(name, help string, opts metrics.MetricOpts, labels ...string) metrics.CounterVecMetric {
+	return counterVec{p.register(name, help, KindCounter, opts, labels)}
+}
+
+// NewGauge creates a new in-memory gauge vector metric.
+func (p *Provider) NewGauge(name, help string, labels ...string) metrics.GaugeVecMetric {
+	return p.NewGaugeWithOpts(name, help, metrics.MetricOpts{}, labels...)
+}
+
+// NewGaugeWithOpts creates a new in-memory gauge vector metric defined by opts.
+func (p *Provider) NewGaugeWithOpts(name, help string, opts metrics.MetricOpts, labels ...string) metrics.GaugeVecMetric {
+	return gaugeVec{p.register(name, help, KindGauge, opts, labels)}
+}
+
+// NewHistogram creates a new in-memory histogram vector metric.
+func (p *Provider) NewHistogram(name, help string, buckets []float64, labels ...string) metrics.ObserverVecMetric {
+	return p.NewHistogramWithOpts(name, help, metrics.MetricOpts{Buckets: buckets}, labels...)
+}
+
+// NewHistogramWithOpts creates a new in-memory histogram vector metric defined by opts.
+func (p *Provider) NewHistogramWithOpts(name, help string, opts metrics.MetricOpts, labels ...string) metrics.ObserverVecMetric {
+	return observerVec{p.register(name, help, KindHistogram, opts, labels)}
+}
+
+// NewSummary creates a new in-memory summary vector metric.
+func (p *Provider) NewSummary(name, help string, labels ...string) metrics.ObserverVecMetric {
+	return p.NewSummaryWithOpts(name, help, metrics.MetricOpts{}, labels...)
+}
+
+// NewSummaryWithOpts creates a new in-memory summary vector metric defined by opts.
+func (p *Provider) NewSummaryWithOpts(name, help string, opts metrics.MetricOpts, labels ...string) metrics.ObserverVecMetric {
+	return observerVec{p.register(name, help, KindSummary, opts, labels)}
+}
+
+// NewObservableGauge creates a new in-memory gauge metric whose values are read from callback when looked up.
+func (p *Provider) NewObservableGauge(name, help string, callback func() []metrics.Observation, labels ...string) {
+	p.register(name, help, KindGauge, metrics.MetricOpts{}, labels).metric.callback = callback
+}
+
+// NewObservableCounter creates a new in-memory counter metric whose values are read from callback when looked up.
+func (p *Provider) NewObservableCounter(name, help string, callback func() []metrics.Observation, labels ...string) {
+	p.register(name, help, KindCounter, metrics.MetricOpts{}, labels).metric.callback = callback
+}
+
+// register returns the existing metric with the same name and kind, or creates a new one.
+// The metric is named after opts.FullName.
+func (p *Provider) register(name, help string, kind Kind, opts metrics.MetricOpts, labels []string) *metricRef {
+	name = opts.FullName(name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m, ok := p.metrics[name]
+	if ok {
+		if m.kind != kind {
+			panic(fmt.Sprintf("metricstest: metric %q already registered as %s", name, m.kind))
+		}
+	} else {
+		m = &metric{
+			name:    name,
+			help:    help,
+			kind:    kind,
+			labels:  append([]string{}, labels...),
+			buckets: append([]float64{}, opts.Buckets...),
+			opts:    opts,
+			series:  map[string]*series{},
+		}
+		p.metrics[name] = m
+	}
+
+	return &metricRef{provider: p, metric: m}
+}
+
+// Unregister removes the metric name with its recorded values, and returns false if there is no such metric.
+func (p *Provider) Unregister(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.metrics[name]
+	delete(p.metrics, name)
+	return ok
+}
+
+// Names returns the sorted names of every metric created with the provider.
+func (p *Provider) Names() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := make([]string, 0, len(p.metrics))
+	for name := range p.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MetricOpts returns the options the metric was defined with, and false if there is no such metric.
+func (p *Provider) MetricOpts(name string) (metrics.MetricOpts, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	m, ok := p.metrics[name]
+	if !ok {
+		return metrics.MetricOpts{}, false
+	}
+	return m.opts, true
+}
+
+// Reset removes every recorded value while keeping the metric definitions.
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, m := range p.metrics {
+		m.series = map[string]*series{}
+	}
+}
+
+// Value returns the current value of a counter or gauge with the given labels,
+// and false if nothing was recorded.
+func (p *Provider) Value(name string, labels map[string]string) (float64, bool) {
+	p.collect(name)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	s, ok := p.lookup(name, labels)
+	if !ok {
+		return 0, false
+	}
+	return s.value, true
+}
+
+// ObservationsOf returns a copy of the values observed by a histogram or summary with the given labels,
+// in the order they were observed.
+func (p *Provider) ObservationsOf(name string, labels map[string]string) []float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	s, ok := p.lookup(name, labels)
+	if !ok {
+		return nil
+	}
+	return append([]float64{}, s.observations...)
+}
+
+// LabelSets returns every label set recorded for the metric.
+func (p *Provider) LabelSets(name string) []map[string]string {
+	p.collect(name)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	m, ok := p.metrics[name]
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	labelSets := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		labelSets = append(labelSets, copyLabels(m.series[key].labels))
+	}
+	return labelSets
+}
+
+// collect replaces the recorded values of an observable metric with the ones read from its callback.
+func (p *Provider) collect(name string) {
+	p.mu.RLock()
+	m, ok := p.metrics[name]
+	p.mu.RUnlock()
+	if !ok || m.callback == nil {
+		return
+	}
+
+	observations := m.callback()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m.series = make(map[string]*series, len(observations))
+	for _, observation := range observations {
+		m.series[labelsKey(observation.Labels)] = &series{labels: copyLabels(observation.Labels), value: observation.Value}
+	}
+}
+
+func (p *Provider) lookup(name string, labels map[string]string) (*series, bool) {
+	m, ok := p.metrics[name]
+	if !ok {
+		return nil, false
+	}
+	s, ok := m.series[labelsKey(labels)]
+	return s, ok
+}
+
+// metricRef is the handle returned to the instrumented code for one metric.
+type metricRef struct {
+	provider *Provider
+	metric   *metric
+}
+
+// with returns the series of labels, with the const labels of the metric added.
+func (r *metricRef) with(labels map[string]string) *seriesRef {
+	if err := r.validate(labels); err != nil {
+		panic(err)
+	}
+	if len(r.metric.opts.ConstLabels) > 0 {
+		labels = copyLabels(labels)
+		for k, v := range r.metric.opts.ConstLabels {
+			labels[k] = v
+		}
+	}
+	return &seriesRef{provider: r.provider, metric: r.metric, key: labelsKey(labels), labels: copyLabels(labels)}
+}
+
+func (r *metricRef) validate(labels map[string]string) error {
+	if len(labels) != len(r.metric.labels) {
+		return fmt.Errorf("metricstest: inconsistent label cardinality for %q: expected %d label values but got %d in %v",
+			r.metric.name, len(r.metric.labels), len(labels), labels)
+	}
+	for _, label := range r.metric.labels {
+		if _, ok := labels[label]; !ok {
+			return fmt.Errorf("metricstest: label %q missing for %q in %v", label, r.metric.name, labels)
+		}
+	}
+	return nil
+}
+
+type seriesRef struct {
+	provider *Provider
+	metric   *metric
+	key      string
+	labels   map[string]string
+}
+
+func (s *seriesRef) update(f func(*series)) {
+	s.provider.mu.Lock()
+	defer s.provider.mu.Unlock()
+
+	v, ok := s.metric.series[s.key]
+	if !ok {
+		v = &series{labels: s.labels}
+		s.metric.series[s.key] = v
+	}
+	f(v)
+}
+
+// counterVec represents an internal counter vec type that implements metrics.CounterVecMetric
+type counterVec struct {
+	*metricRef
+}
+
+func (c counterVec) With(labels map[string]string) metrics.CounterMetric {
+	return counter{c.with(labels)}
+}
+
+type counter struct {
+	*seriesRef
+}
+
+func (c counter) Inc() {
+	c.Add(1)
+}
+
+func (c counter) Add(v float64) {
+	if v < 0 {
+		panic(fmt.Sprintf("metricstest: counter %q cannot decrease in value", c.metric.name))
+	}
+	c.update(func(s *series) { s.value += v })
+}
+
+// gaugeVec represents an internal gauge vec type that implements metrics.GaugeVecMetric
+type gaugeVec struct {
+	*metricRef
+}
+
+func (g gaugeVec) With(labels map[string]string) metrics.GaugeMetric {
+	return gauge{g.with(labels)}
+}
+
+type gauge struct {
+	*seriesRef
+}
+
+func (g gauge) Set(v float64) {
+	g.update(func(s *series) { s.value = v })
+}
+
+func (g gauge) Inc() {
+	g.Add(1)
+}
+
+func (g gauge) Dec() {
+	g.Add(-1)
+}
+
+func (g gauge) Add(v float64) {
+	g.update(func(s *series) { s.value += v })
+}
+
+func (g gauge) Sub(v float64) {
+	g.Add(-v)
+}
+
+func (g gauge) SetToCurrentTime() {
+	g.Set(float64(time.Now().UnixNano()) / 1e9)
+}
+
+// observerVec represents an internal histogram/summary vec type that implements metrics.ObserverVecMetric
+type observerVec struct {
+	*metricRef
+}
+
+func (o observerVec) With(labels map[string]string) metrics.ObserverMetric {
+	return observer{o.with(labels)}
+}
+
+type observer struct {
+	*seriesRef
+}
+
+func (o observer) Observe(v float64) {
+	o.update(func(s *series) { s.observations = append(s.observations, v) })
+}
+
+func labelsKey(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\xff")
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metricstest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AccelByte/observability-go-sdk/metrics"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProviderWithRestfulFilter(t *testing.T) {
+	p := NewProvider()
+	client := metrics.NewClient("test", metrics.BuildInfo{Version: "1.0.0"}, &metrics.Opts{Provider: p})
+
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/{namespace}/bans").To(func(req *restful.Request, resp *restful.Response) {
+		resp.WriteHeader(http.StatusNoContent)
+	}))
+	container := restful.NewContainer()
+	container.Filter(client.RestfulFilter())
+	container.Add(ws)
+
+	container.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/accelbyte/bans", nil))
+
+	p.AssertObservationCount(t, "ab.service_request_http", map[string]string{
+		"namespace":     "accelbyte",
+		"path":          "/{namespace}/bans",
+		"method":        http.MethodGet,
+		"response_code": "204",
+	}, 1)
+	p.AssertGauge(t, "ab.test_build_info", map[string]string{
+		"revisionID":         "",
+		"buildDate":          "",
+		"version":            "1.0.0",
+		"gitHash":            "",
+		"roleSeedingVersion": "",
+	}, 1)
+}
+
+func TestProviderWithDBMetrics(t *testing.T) {
+	p := NewProvider()
+	metrics.SetProvider(p)
+	defer metrics.SetProvider(metrics.NewPrometheusProvider(metrics.PrometheusProviderOpts{}))
+	metrics.Initialize("test", metrics.BuildInfo{}, &metrics.Opts{})
+
+	dbMetrics := metrics.NewDBMetrics(metrics.DefaultProvider, "bans")
+	call := dbMetrics.NewCall("get_ban")
+	call.Error()
+	call.CallEnded()
+
+	labels := map[string]string{"action": "get_ban", "result": "error"}
+	assert.Len(t, p.ObservationsOf("ab.test_bans_db_latency_seconds", labels), 1)
+
+	metrics.CounterVec("ab.test_counter", "counter", []string{"namespace"}).
+		With(map[string]string{"namespace": "accelbyte"}).Add(2)
+	p.AssertCounter(t, "ab.test_counter", map[string]string{"namespace": "accelbyte"}, 2)
+
+	p.Reset()
+	assert.Empty(t, p.ObservationsOf("ab.test_bans_db_latency_seconds", labels))
+}
+
+func TestProviderWithOpts(t *testing.T) {
+	p := NewProvider()
+	opts := metrics.MetricOpts{
+		Namespace:   "ab",
+		Unit:        "seconds",
+		ConstLabels: map[string]string{"region": "us"},
+		Objectives:  map[float64]float64{0.99: 0.001},
+	}
+	p.NewSummaryWithOpts("latency", "summary", opts, "method").With(map[string]string{"method": "GET"}).Observe(0.5)
+
+	p.AssertObservationCount(t, "ab_latency_seconds", map[string]string{"method": "GET", "region": "us"}, 1)
+	got, ok := p.MetricOpts("ab_latency_seconds")
+	assert.True(t, ok)
+	assert.Equal(t, opts, got)
+}
+
+func TestProviderPanicsOnInconsistentLabels(t *testing.T) {
+	p := NewProvider()
+	vec := p.NewCounter("ab.test_counter", "counter", "namespace")
+
+	assert.Panics(t, func() { vec.With(map[string]string{"user_id": "1"}) })
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
+	"google.golang.org/grpc"
+)
+
+const (
+	otelMeterName             = "github.com/AccelByte/observability-go-sdk/metrics"
+	defaultOTelConnectTimeout = 5 * time.Second
+)
+
+// OTelProviderOpts represents the OpenTelemetry metrics configuration options.
+type OTelProviderOpts struct {
+	ServiceName    string        // the service name used to identify the metrics in backends
+	Endpoint       string        // OTLP/gRPC collector endpoint, usually the same one passed to trace.SetUpTracer
+	ConnectTimeout time.Duration // default is 5 seconds
+	ExportInterval time.Duration // default is the OpenTelemetry SDK default (60 seconds)
+
+	// Reader overrides the OTLP exporter with a custom metric reader, i.e. sdkmetric.NewManualReader() in tests.
+	// Endpoint and ConnectTimeout are ignored when it is set.
+	Reader sdkmetric.Reader
+
+	// Views customize the metrics of the meter provider, e.g. an sdkmetric.AggregationBase2ExponentialHistogram
+	// aggregation for the histograms to export as exponential histograms. The instruments are matched by their
+	// sanitized name, e.g. ab_bans_db_latency_seconds.
+	Views []sdkmetric.View
+}
+
+// OTelProvider represents the implementation for OpenTelemetry provider.
+type OTelProvider struct {
+	meterProvider *sdkmetric.MeterProvider
+	meter         metric.Meter
+
+	mu     sync.Mutex
+	gauges map[string]*otelGaugeVec
+}
+
+// NewOTelProvider creates a new OpenTelemetry provider that implements Provider using the OpenTelemetry metric SDK
+// and exports the metrics over OTLP/gRPC to opts.Endpoint.
+// If a connection is not established within opts.ConnectTimeout, it is aborted and returns an error.
+// The returned function flushes the pending metrics and shuts the provider down.
+func NewOTelProvider(ctx context.Context, opts OTelProviderOpts) (*OTelProvider, func(), error) {
+	reader := opts.Reader
+	if reader == nil {
+		connectTimeout := opts.ConnectTimeout
+		if connectTimeout <= 0 {
+			connectTimeout = defaultOTelConnectTimeout
+		}
+		connectCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+		defer cancel()
+
+		exporter, err := otlpmetricgrpc.New(connectCtx,
+			otlpmetricgrpc.WithInsecure(),
+			otlpmetricgrpc.WithEndpoint(opts.Endpoint),
+			otlpmetricgrpc.WithDialOption(grpc.WithBlock()))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to set up metric exporter: %w", err)
+		}
+
+		var readerOpts []sdkmetric.PeriodicReaderOption
+		if opts.ExportInterval > 0 {
+			readerOpts = append(readerOpts, sdkmetric.WithInterval(opts.ExportInterval))
+		}
+		reader = sdkmetric.NewPeriodicReader(exporter, readerOpts...)
+	}
+
+	resc, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(
+			// the service name used to display metrics in backends
+			semconv.ServiceNameKey.String(opts.ServiceName),
+		),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(resc), sdkmetric.WithView(opts.Views...))
+	p := &OTelProvider{
+		meterProvider: mp,
+		meter:         mp.Meter(otelMeterName),
+		gauges:        map[string]*otelGaugeVec{},
+	}
+
+	return p, func() {
+		if err := mp.ForceFlush(ctx); err != nil {
+			log.Print(err)
+		}
+		if err := mp.Shutdown(ctx); err != nil {
+			log.Print(err)
+		}
+	}, nil
+}
+
+// NewCounter creates a new OpenTelemetry counter metric.
+// The labels are not enforced, every label passed to With is recorded as an attribute.
+func (p *OTelProvider) NewCounter(name, help string, labels ...string) CounterVecMetric {
+	return p.NewCounterWithOpts(name, help, MetricOpts{}, labels...)
+}
+
+// NewCounterWithOpts creates a new OpenTelemetry counter metric defined by opts.
+func (p *OTelProvider) NewCounterWithOpts(name, help string, opts MetricOpts, labels ...string) CounterVecMetric {
+	counter, err := p.meter.Float64Counter(sanitizeOTelName(opts.FullName(name)),
+		metric.WithDescription(help), metric.WithUnit(opts.Unit))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return otelCounterVec{Float64Counter: counter, constLabels: opts.ConstLabels}
+}
+
+// otelCounterVec represents an internal counter type that implements CounterVecMetric
+type otelCounterVec struct {
+	metric.Float64Counter
+	constLabels map[string]string
+}
+
+func (c otelCounterVec) With(labels map[string]string) CounterMetric {
+	return otelCounter{counter: c.Float64Counter, attrs: labelsToAttributeSet(mergeLabels(c.constLabels, labels))}
+}
+
+type otelCounter struct {
+	counter metric.Float64Counter
+	attrs   attribute.Set
+}
+
+func (c otelCounter) Inc() {
+	c.Add(1)
+}
+
+func (c otelCounter) Add(v float64) {
+	c.counter.Add(context.Background(), v, metric.WithAttributeSet(c.attrs))
+}
+
+// NewGauge creates a new OpenTelemetry gauge metric.
+// The last value set for every label set is reported through an asynchronous gauge on each collection.
+func (p *OTelProvider) NewGauge(name, help string, labels ...string) GaugeVecMetric {
+	return p.NewGaugeWithOpts(name, help, MetricOpts{}, labels...)
+}
+
+// NewGaugeWithOpts creates a new OpenTelemetry gauge metric defined by opts.
+// Creating a gauge with the name of an existing one returns the existing one.
+func (p *OTelProvider) NewGaugeWithOpts(name, help string, opts MetricOpts, labels ...string) GaugeVecMetric {
+	otelName := sanitizeOTelName(opts.FullName(name))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if vec, ok := p.gauges[otelName]; ok {
+		return vec
+	}
+
+	vec := &otelGaugeVec{gauges: map[attribute.Distinct]*otelGauge{}, constLabels: opts.ConstLabels}
+	_, err := p.meter.Float64ObservableGauge(otelName,
+		metric.WithDescription(help),
+		metric.WithUnit(opts.Unit),
+		metric.WithFloat64Callback(vec.observe))
+	if err != nil {
+		otel.Handle(err)
+	}
+	p.gauges[otelName] = vec
+	return vec
+}
+
+// otelGaugeVec represents an internal gauge type that implements GaugeVecMetric
+type otelGaugeVec struct {
+	mu          sync.RWMutex
+	gauges      map[attribute.Distinct]*otelGauge
+	constLabels map[string]string
+}
+
+func (g *otelGaugeVec) With(labels map[string]string) GaugeMetric {
+	attrs := labelsToAttributeSet(mergeLabels(g.constLabels, labels))
+	key := attrs.Equivalent()
+
+	g.mu.RLock()
+	gauge, ok := g.gauges[key]
+	g.mu.RUnlock()
+	if ok {
+		return gauge
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if gauge, ok = g.gauges[key]; !ok {
+		gauge = &otelGauge{attrs: attrs}
+		g.gauges[key] = gauge
+	}
+	return gauge
+}
+
+func (g *otelGaugeVec) observe(_ context.Context, o metric.Float64Observer) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, gauge := range g.gauges {
+		o.Observe(gauge.value(), metric.WithAttributeSet(gauge.attrs))
+	}
+	return nil
+}
+
+type otelGauge struct {
+	attrs attribute.Set
+	bits  uint64
+}
+
+func (g *otelGauge) value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+func (g *otelGauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+func (g *otelGauge) Inc() {
+	g.Add(1)
+}
+
+func (g *otelGauge) Dec() {
+	g.Add(-1)
+}
+
+func (g *otelGauge) Add(v float64) {
+	for {
+		oldBits := atomic.LoadUint64(&g.bits)
+		newBits := math.Float64bits(math.Float64frombits(oldBits) + v)
+		if atomic.CompareAndSwapUint64(&g.bits, oldBits, newBits) {
+			return
+		}
+	}
+}
+
+func (g *otelGauge) Sub(v float64) {
+	g.Add(-v)
+}
+
+func (g *otelGauge) SetToCurrentTime() {
+	g.Set(float64(time.Now().UnixNano()) / 1e9)
+}
+
+// NewHistogram creates a new OpenTelemetry histogram metric with explicit bucket boundaries.
+func (p *OTelProvider) NewHistogram(name, help string, buckets []float64, labels ...string) ObserverVecMetric {
+	return p.NewHistogramWithOpts(name, help, MetricOpts{Buckets: buckets}, labels...)
+}
+
+// NewHistogramWithOpts creates a new OpenTelemetry histogram metric defined by opts.
+// opts.NativeHistogram is ignored, the exponential histograms are configured with OTelProviderOpts.Views.
+func (p *OTelProvider) NewHistogramWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric {
+	buckets := opts.Buckets
+	if len(buckets) <= 0 {
+		buckets = prometheus.DefBuckets
+	}
+	histogram, err := p.meter.Float64Histogram(sanitizeOTelName(opts.FullName(name)),
+		metric.WithDescription(help),
+		metric.WithUnit(opts.Unit),
+		metric.WithExplicitBucketBoundaries(buckets...))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return otelHistogramVec{Float64Histogram: histogram, constLabels: opts.ConstLabels}
+}
+
+// NewSummary creates a new OpenTelemetry histogram metric with the default buckets,
+// since OpenTelemetry does not support summary metrics.
+func (p *OTelProvider) NewSummary(name, help string, labels ...string) ObserverVecMetric {
+	return p.NewSummaryWithOpts(name, help, MetricOpts{}, labels...)
+}
+
+// NewSummaryWithOpts creates a new OpenTelemetry histogram metric defined by opts, since OpenTelemetry
+// does not support summary metrics. The quantiles are computed by the backend from the buckets,
+// so opts.Objectives, opts.MaxAge and opts.AgeBuckets are ignored.
+func (p *OTelProvider) NewSummaryWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric {
+	return p.NewHistogramWithOpts(name, help, opts, labels...)
+}
+
+// otelHistogramVec represents an internal histogram type that implements ObserverVecMetric
+type otelHistogramVec struct {
+	metric.Float64Histogram
+	constLabels map[string]string
+}
+
+func (h otelHistogramVec) With(labels map[string]string) ObserverMetric {
+	return otelHistogram{histogram: h.Float64Histogram, attrs: labelsToAttributeSet(mergeLabels(h.constLabels, labels))}
+}
+
+type otelHistogram struct {
+	histogram metric.Float64Histogram
+	attrs     attribute.Set
+}
+
+func (h otelHistogram) Observe(v float64) {
+	h.histogram.Record(context.Background(), v, metric.WithAttributeSet(h.attrs))
+}
+
+// NewObservableGauge creates a new OpenTelemetry asynchronous gauge whose values are read from callback
+// on each collection.
+func (p *OTelProvider) NewObservableGauge(name, help string, callback func() []Observation, labels ...string) {
+	_, err := p.meter.Float64ObservableGauge(sanitizeOTelName(name),
+		metric.WithDescription(help),
+		metric.WithFloat64Callback(observeFunc(callback)))
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// NewObservableCounter creates a new OpenTelemetry asynchronous counter whose values are read from callback
+// on each collection. The callback must report cumulative values.
+func (p *OTelProvider) NewObservableCounter(name, help string, callback func() []Observation, labels ...string) {
+	_, err := p.meter.Float64ObservableCounter(sanitizeOTelName(name),
+		metric.WithDescription(help),
+		metric.WithFloat64Callback(observeFunc(callback)))
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+func observeFunc(callback func() []Observation) metric.Float64Callback {
+	return func(_ context.Context, o metric.Float64Observer) error {
+		for _, observation := range callback() {
+			o.Observe(observation.Value, metric.WithAttributeSet(labelsToAttributeSet(observation.Labels)))
+		}
+		return nil
+	}
+}
+
+// mergeLabels returns the labels with the const labels added.
+func mergeLabels(constLabels, labels map[string]string) map[string]string {
+	if len(constLabels) == 0 {
+		return labels
+	}
+	merged := make(map[string]string, len(constLabels)+len(labels))
+	for k, v := range constLabels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+func labelsToAttributeSet(labels map[string]string) attribute.Set {
+	attrs := make([]attribute.KeyValue, 0, len(labels))
+	for k, v := range labels {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	return attribute.NewSet(attrs...)
+}
+
+// sanitizeOTelName sanitizes the name the same way as Prometheus metrics so both providers
+// export the same metric names, and additionally replaces ':' which is not allowed in OpenTelemetry.
+func sanitizeOTelName(name string) string {
+	return strings.ReplaceAll(sanitizeName(name), ":", "_")
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOTelProvider(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p, shutdown, err := NewOTelProvider(context.Background(), OTelProviderOpts{ServiceName: "test", Reader: reader})
+	require.NoError(t, err)
+	defer shutdown()
+
+	labels := map[string]string{"namespace": "accelbyte"}
+	p.NewCounter("ab.test_counter", "counter", "namespace").With(labels).Add(2)
+	gauge := p.NewGauge("ab.test_gauge", "gauge", "namespace").With(labels)
+	gauge.Set(5)
+	gauge.Dec()
+	p.NewHistogram("ab.test_histogram", "histogram", []float64{1, 2}, "namespace").With(labels).Observe(1.5)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	got := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m.Data
+	}
+	attrs := attribute.NewSet(attribute.String("namespace", "accelbyte"))
+
+	counter := got["ab_test_counter"].(metricdata.Sum[float64])
+	assert.Equal(t, 2.0, counter.DataPoints[0].Value)
+	assert.Equal(t, attrs, counter.DataPoints[0].Attributes)
+
+	gaugeData := got["ab_test_gauge"].(metricdata.Gauge[float64])
+	assert.Equal(t, 4.0, gaugeData.DataPoints[0].Value)
+
+	histogram := got["ab_test_histogram"].(metricdata.Histogram[float64])
+	assert.Equal(t, []float64{1, 2}, histogram.DataPoints[0].Bounds)
+	assert.Equal(t, []uint64{0, 1, 0}, histogram.DataPoints[0].BucketCounts)
+}
+
+func TestOTelProviderWithOpts(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p, shutdown, err := NewOTelProvider(context.Background(), OTelProviderOpts{ServiceName: "test", Reader: reader})
+	require.NoError(t, err)
+	defer shutdown()
+
+	opts := MetricOpts{Namespace: "ab", Subsystem: "bans", Unit: "seconds", ConstLabels: map[string]string{"region": "us"}}
+	p.NewSummaryWithOpts("latency", "summary", opts, "method").With(map[string]string{"method": "GET"}).Observe(0.5)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	m := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "ab_bans_latency_seconds", m.Name)
+	assert.Equal(t, "seconds", m.Unit)
+	histogram := m.Data.(metricdata.Histogram[float64])
+	assert.Equal(t, attribute.NewSet(attribute.String("method", "GET"), attribute.String("region", "us")),
+		histogram.DataPoints[0].Attributes)
+}
+
+func TestSanitizeOTelName(t *testing.T) {
+	assert.Equal(t, "ab_service__gc_cycles_automatic_gc_cycles",
+		sanitizeOTelName("ab.service_/gc/cycles/automatic:gc-cycles"))
+}
+
+func TestOTelProviderViews(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	view := sdkmetric.NewView(sdkmetric.Instrument{Name: "ab_test_latency"},
+		sdkmetric.Stream{Aggregation: sdkmetric.AggregationBase2ExponentialHistogram{MaxSize: 160, MaxScale: 20}})
+	p, shutdown, err := NewOTelProvider(context.Background(), OTelProviderOpts{ServiceName: "test", Reader: reader, Views: []sdkmetric.View{view}})
+	require.NoError(t, err)
+	defer shutdown()
+
+	p.NewHistogram("ab.test_latency", "latency", nil).With(map[string]string{}).Observe(1.5)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	assert.IsType(t, metricdata.ExponentialHistogram[float64]{}, rm.ScopeMetrics[0].Metrics[0].Data)
+}
+
+func TestOTelProviderGaugeCached(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	p, shutdown, err := NewOTelProvider(context.Background(), OTelProviderOpts{ServiceName: "test", Reader: reader})
+	require.NoError(t, err)
+	defer shutdown()
+
+	p.NewGauge("ab.test_gauge", "gauge").With(map[string]string{}).Set(1)
+	p.NewGauge("ab.test_gauge", "gauge").With(map[string]string{}).Set(2)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	gauge := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Gauge[float64])
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, 2.0, gauge.DataPoints[0].Value)
+}
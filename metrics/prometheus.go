@@ -5,161 +5,363 @@
 package metrics
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
-	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-var (
-	registerer = prometheus.DefaultRegisterer
-	gatherer   = prometheus.DefaultGatherer
-)
-
 // PrometheusProviderOpts represents the Prometheus metrics configuration options.
 type PrometheusProviderOpts struct {
+	// Registerer is where the metrics are registered, default is prometheus.DefaultRegisterer.
+	// Set it to a prometheus.NewRegistry() to give the provider a registry of its own.
 	prometheus.Registerer
+	// Gatherer is what Handler serves, default is Registerer if it is a prometheus.Gatherer, such as a
+	// *prometheus.Registry, or else prometheus.DefaultGatherer.
 	prometheus.Gatherer
-	DisableGoCollector      bool // default is false = go collector is enabled
-	DisableProcessCollector bool // default is false = process collector is enabled
+	DisableGoCollector        bool // default is false = go collector is enabled
+	DisableProcessCollector   bool // default is false = process collector is enabled
+	DisableBuildInfoCollector bool // default is false = build info collector is enabled
+	// NativeHistograms makes every histogram created by NewHistogram native, default is classic histograms.
+	NativeHistograms *NativeHistogramOpts
+	// ErrorHandler is called instead of panicking with a *LabelError when With is called with labels not matching
+	// the declared ones, and with a *RegistrationError when a metric conflicts with an already registered one.
+	// Default is to log the error.
+	ErrorHandler func(err error)
 }
 
 // NewPrometheusProvider creates a new Prometheus provider that implements Provider using Prometheus metrics.
+// The Go, process and build info collectors are registered unless disabled, and unregistered from
+// opts.Registerer if disabled, e.g. to remove the ones registered by default in prometheus.DefaultRegisterer.
 func NewPrometheusProvider(opts PrometheusProviderOpts) PrometheusProvider {
-	if opts.Registerer != nil {
-		registerer = opts.Registerer
+	registerer := opts.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
 	}
-	if opts.Gatherer != nil {
-		gatherer = opts.Gatherer
+	gatherer := opts.Gatherer
+	if gatherer == nil {
+		var ok bool
+		if gatherer, ok = registerer.(prometheus.Gatherer); !ok {
+			gatherer = prometheus.DefaultGatherer
+		}
+	}
+	errorHandler := opts.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(err error) {
+			log.Print(err)
+		}
 	}
 	p := PrometheusProvider{
-		registerer: registerer,
-		gatherer:   gatherer,
+		registerer:       registerer,
+		gatherer:         gatherer,
+		nativeHistograms: opts.NativeHistograms,
+		errorHandler:     errorHandler,
+		labelValidator:   newLabelValidator(registerer, errorHandler),
+		collectors:       newCollectorSet(),
+		handler:          &lazyHandler{},
 	}
 
-	if opts.DisableProcessCollector {
-		prometheus.Unregister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
-	}
-	if opts.DisableGoCollector {
-		prometheus.Unregister(collectors.NewGoCollector())
-	}
+	p.registerDefaultCollector(collectors.NewGoCollector(), opts.DisableGoCollector)
+	p.registerDefaultCollector(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), opts.DisableProcessCollector)
+	p.registerDefaultCollector(collectors.NewBuildInfoCollector(), opts.DisableBuildInfoCollector)
 
 	return p
 }
 
+// registerDefaultCollector registers collector, or unregisters the equivalent collector if disabled.
+func (p PrometheusProvider) registerDefaultCollector(collector prometheus.Collector, disabled bool) {
+	if disabled {
+		p.registerer.Unregister(collector)
+		return
+	}
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if err := p.registerer.Register(collector); err != nil && !errors.As(err, &alreadyRegistered) {
+		p.reportError(err)
+	}
+}
+
 // PrometheusProvider represents the implementation for Prometheus provider.
+// Creating a metric already registered with the same name, help and labels returns the existing one,
+// e.g. when several clients share a registry, while a conflicting metric is reported to
+// PrometheusProviderOpts.ErrorHandler and returned by MetricRegistrationError. The labels passed to With are validated: the missing labels are
+// set to an empty value, the unknown ones are dropped, and the error is reported to
+// PrometheusProviderOpts.ErrorHandler and counted in ab_metrics_label_errors_total.
 type PrometheusProvider struct {
-	registerer prometheus.Registerer
-	gatherer   prometheus.Gatherer
+	registerer       prometheus.Registerer
+	gatherer         prometheus.Gatherer
+	nativeHistograms *NativeHistogramOpts
+	errorHandler     func(err error)
+	labelValidator   *labelValidator
+	collectors       *collectorSet
+	handler          *lazyHandler
 }
 
 // NewCounter creates a new Prometheus counter vector metric.
 func (p PrometheusProvider) NewCounter(name, help string, labels ...string) CounterVecMetric {
-	vec := promauto.With(p.registerer).NewCounterVec(
+	return p.NewCounterWithOpts(name, help, MetricOpts{}, labels...)
+}
+
+// NewCounterWithOpts creates a new Prometheus counter vector metric defined by opts.
+func (p PrometheusProvider) NewCounterWithOpts(name, help string, opts MetricOpts, labels ...string) CounterVecMetric {
+	vec := prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: sanitizeName(name),
-			Help: help,
+			Name:        sanitizeName(opts.FullName(name)),
+			Help:        help,
+			ConstLabels: opts.ConstLabels,
 		},
 		labels,
 	)
-	return counterVec{vec}
+	vec, err := registerVec(p, sanitizeName(opts.FullName(name)), vec)
+	return counterVec{vec, p.labelSet(opts.FullName(name), labels), err}
 }
 
 // counterVec represents an internal counter vec type that implements CounterVecMetric
 type counterVec struct {
 	*prometheus.CounterVec
+	labels labelSet
+	err    error
 }
 
 func (c counterVec) With(labels map[string]string) CounterMetric {
-	return c.CounterVec.With(labels)
+	return c.CounterVec.With(c.labels.fix(labels))
+}
+
+func (c counterVec) registrationError() error {
+	return c.err
 }
 
 // NewGauge creates a new Prometheus gauge vector metric.
 func (p PrometheusProvider) NewGauge(name, help string, labels ...string) GaugeVecMetric {
-	vec := promauto.With(p.registerer).NewGaugeVec(
+	return p.NewGaugeWithOpts(name, help, MetricOpts{}, labels...)
+}
+
+// NewGaugeWithOpts creates a new Prometheus gauge vector metric defined by opts.
+func (p PrometheusProvider) NewGaugeWithOpts(name, help string, opts MetricOpts, labels ...string) GaugeVecMetric {
+	vec := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: sanitizeName(name),
-			Help: help,
+			Name:        sanitizeName(opts.FullName(name)),
+			Help:        help,
+			ConstLabels: opts.ConstLabels,
 		},
 		labels,
 	)
-	return gaugeVec{vec}
+	vec, err := registerVec(p, sanitizeName(opts.FullName(name)), vec)
+	return gaugeVec{vec, p.labelSet(opts.FullName(name), labels), err}
 }
 
 // gaugeVec represents an internal gauge vec type that implements GaugeVecMetric
 type gaugeVec struct {
 	*prometheus.GaugeVec
+	labels labelSet
+	err    error
 }
 
 func (g gaugeVec) With(labels map[string]string) GaugeMetric {
-	return g.GaugeVec.With(labels)
+	return g.GaugeVec.With(g.labels.fix(labels))
+}
+
+func (g gaugeVec) registrationError() error {
+	return g.err
 }
 
 // NewHistogram creates a new Prometheus histogram vector metric.
+// The histogram is native if the provider was created with PrometheusProviderOpts.NativeHistograms.
 func (p PrometheusProvider) NewHistogram(name, help string, buckets []float64, labels ...string) ObserverVecMetric {
-	if len(buckets) <= 0 {
-		buckets = prometheus.DefBuckets
-	}
-	vec := promauto.With(p.registerer).NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    sanitizeName(name),
-			Help:    help,
-			Buckets: buckets,
-		},
-		labels,
-	)
-	return histogramVec{vec}
+	return p.NewHistogramWithOpts(name, help, MetricOpts{Buckets: buckets}, labels...)
+}
+
+// NewNativeHistogram creates a new Prometheus native histogram vector metric configured by opts.
+// buckets are only used when opts.ClassicBuckets is set, the default buckets being prometheus.DefBuckets.
+func (p PrometheusProvider) NewNativeHistogram(name, help string, buckets []float64, opts NativeHistogramOpts, labels ...string) ObserverVecMetric {
+	return p.NewHistogramWithOpts(name, help, MetricOpts{Buckets: buckets, NativeHistogram: &opts}, labels...)
+}
+
+// NewHistogramWithOpts creates a new Prometheus histogram vector metric defined by opts.
+// The histogram is native if opts.NativeHistogram is set, or else if the provider was created
+// with PrometheusProviderOpts.NativeHistograms.
+func (p PrometheusProvider) NewHistogramWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric {
+	histogramOpts := prometheus.HistogramOpts{
+		Name:        sanitizeName(opts.FullName(name)),
+		Help:        help,
+		ConstLabels: opts.ConstLabels,
+		Buckets:     opts.Buckets,
+	}
+	if len(histogramOpts.Buckets) <= 0 {
+		histogramOpts.Buckets = prometheus.DefBuckets
+	}
+
+	native := opts.NativeHistogram
+	if native == nil {
+		native = p.nativeHistograms
+	}
+	if native != nil {
+		histogramOpts.NativeHistogramBucketFactor = defaultNativeHistogramBucketFactor
+		if native.BucketFactor > 1 {
+			histogramOpts.NativeHistogramBucketFactor = native.BucketFactor
+		}
+		histogramOpts.NativeHistogramMaxBucketNumber = defaultNativeHistogramMaxBucketNumber
+		if native.MaxBucketNumber > 0 {
+			histogramOpts.NativeHistogramMaxBucketNumber = native.MaxBucketNumber
+		}
+		histogramOpts.NativeHistogramZeroThreshold = native.ZeroThreshold
+		histogramOpts.NativeHistogramMinResetDuration = native.MinResetDuration
+		if !native.ClassicBuckets {
+			histogramOpts.Buckets = nil
+		}
+	}
+
+	vec, err := registerVec(p, histogramOpts.Name, prometheus.NewHistogramVec(histogramOpts, labels))
+	if err == nil {
+		err = p.checkBuckets(histogramOpts.Name, histogramOpts.Buckets)
+	}
+	return histogramVec{vec, p.labelSet(opts.FullName(name), labels), err}
 }
 
 // histogramVec represents an internal histogram vec type that implements ObserverVecMetric
 type histogramVec struct {
 	*prometheus.HistogramVec
+	labels labelSet
+	err    error
 }
 
 func (h histogramVec) With(labels map[string]string) ObserverMetric {
-	return h.HistogramVec.With(labels)
+	return h.HistogramVec.With(h.labels.fix(labels))
+}
+
+func (h histogramVec) registrationError() error {
+	return h.err
 }
 
-// NewSummary creates a new Prometheus summary vector metric.
+// NewSummary creates a new Prometheus summary vector metric with the DefaultObjectives quantiles.
 func (p PrometheusProvider) NewSummary(name, help string, labels ...string) ObserverVecMetric {
-	vec := promauto.With(p.registerer).NewSummaryVec(
+	return p.NewSummaryWithOpts(name, help, MetricOpts{}, labels...)
+}
+
+// NewSummaryWithOpts creates a new Prometheus summary vector metric defined by opts.
+func (p PrometheusProvider) NewSummaryWithOpts(name, help string, opts MetricOpts, labels ...string) ObserverVecMetric {
+	objectives := opts.Objectives
+	if objectives == nil {
+		objectives = DefaultObjectives
+	}
+	vec := prometheus.NewSummaryVec(
 		prometheus.SummaryOpts{
-			Name: sanitizeName(name),
-			Help: help,
+			Name:        sanitizeName(opts.FullName(name)),
+			Help:        help,
+			ConstLabels: opts.ConstLabels,
+			Objectives:  objectives,
+			MaxAge:      opts.MaxAge,
+			AgeBuckets:  opts.AgeBuckets,
 		},
 		labels,
 	)
-	return summaryVec{vec}
+	vec, err := registerVec(p, sanitizeName(opts.FullName(name)), vec)
+	return summaryVec{vec, p.labelSet(opts.FullName(name), labels), err}
 }
 
-// initBuildInfo initializes one gauge metric with constant 1
-func (p PrometheusProvider) initBuildInfo(buildInfo BuildInfo) {
-	buildInfoGauge := promauto.With(p.registerer).NewGauge(
-		prometheus.GaugeOpts{
-			Name: sanitizeName(generateMetricsName(serviceName, "build_info")),
-			Help: "A metric with a constant '1' value labeled by version, revision, branch, and goversion from which the service was built",
-			ConstLabels: prometheus.Labels{
-				"revisionID":         buildInfo.RevisionID,
-				"buildDate":          buildInfo.BuildDate,
-				"version":            buildInfo.Version,
-				"gitHash":            buildInfo.GitHash,
-				"roleSeedingVersion": buildInfo.RoleSeedingVersion,
-			},
-		})
-	buildInfoGauge.Set(1)
+func (p PrometheusProvider) labelSet(name string, labels []string) labelSet {
+	return labelSet{metric: sanitizeName(name), names: labels, validator: p.labelValidator}
+}
+
+// NewObservableGauge registers a Prometheus gauge metric whose values are read from callback at scrape time.
+// An observable metric already registered with the same name, help and labels is replaced.
+func (p PrometheusProvider) NewObservableGauge(name, help string, callback func() []Observation, labels ...string) {
+	p.registerObservable(sanitizeName(name), newObservableCollector(name, help, prometheus.GaugeValue, callback, labels))
+}
+
+// NewObservableCounter registers a Prometheus counter metric whose values are read from callback at scrape time.
+// The callback must report cumulative values. An observable metric already registered with the same name,
+// help and labels is replaced.
+func (p PrometheusProvider) NewObservableCounter(name, help string, callback func() []Observation, labels ...string) {
+	p.registerObservable(sanitizeName(name), newObservableCollector(name, help, prometheus.CounterValue, callback, labels))
+}
+
+// observableCollector represents an internal collector that reports the callback observations as const metrics
+type observableCollector struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	callback  func() []Observation
+	labels    []string
+}
+
+func newObservableCollector(name, help string, valueType prometheus.ValueType, callback func() []Observation, labels []string) *observableCollector {
+	return &observableCollector{
+		desc:      prometheus.NewDesc(sanitizeName(name), help, labels, nil),
+		valueType: valueType,
+		callback:  callback,
+		labels:    labels,
+	}
+}
+
+func (c *observableCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *observableCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, observation := range c.callback() {
+		labelValues := make([]string, len(c.labels))
+		for i, label := range c.labels {
+			labelValues[i] = observation.Labels[label]
+		}
+		metric, err := prometheus.NewConstMetric(c.desc, c.valueType, observation.Value, labelValues...)
+		if err != nil {
+			metric = prometheus.NewInvalidMetric(c.desc, err)
+		}
+		ch <- metric
+	}
 }
 
 // summaryVec represents an internal summary vec type that implements ObserverVecMetric
 type summaryVec struct {
 	*prometheus.SummaryVec
+	labels labelSet
+	err    error
 }
 
 func (s summaryVec) With(labels map[string]string) ObserverMetric {
-	return s.SummaryVec.With(labels)
+	return s.SummaryVec.With(s.labels.fix(labels))
+}
+
+func (s summaryVec) registrationError() error {
+	return s.err
+}
+
+// Handler returns the http.Handler that exposes the metrics of the provider over HTTP.
+// The OpenMetrics format, which carries the exemplars, is served to the scrapers asking for it.
+func (p PrometheusProvider) Handler() http.Handler {
+	if p.handler == nil {
+		return p.newHandler()
+	}
+	return p.handler.get(p.newHandler)
+}
+
+func (p PrometheusProvider) newHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		p.registerer,
+		promhttp.HandlerFor(p.gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	)
+}
+
+// lazyHandler holds the handler of a provider, created on first use.
+type lazyHandler struct {
+	once    sync.Once
+	handler http.Handler
+}
+
+// get returns the handler, created with newHandler on the first call.
+func (h *lazyHandler) get(newHandler func() http.Handler) http.Handler {
+	h.once.Do(func() {
+		h.handler = newHandler()
+	})
+	return h.handler
 }
 
+// defaultGathererHandler serves prometheus.DefaultGatherer when the default provider is not a PrometheusProvider.
+var defaultGathererHandler lazyHandler
+
 // ServePrometheus exposes Prometheus over HTTP on the given address and metrics endpoint.
 // If you plan on exposing the metrics on an already existing HTTP server, use the PrometheusHandler instead.
 func ServePrometheus(addr, endpoint string) error {
@@ -168,12 +370,17 @@ func ServePrometheus(addr, endpoint string) error {
 	return http.ListenAndServe(addr, mux)
 }
 
-// PrometheusHandler creates a new http.Handler that exposes Prometheus metrics over HTTP.
+// PrometheusHandler creates a new http.Handler that exposes the metrics of the default provider over HTTP,
+// using the provider set by SetProvider at the time of the request, unwrapped if it is e.g. a CardinalityLimiter.
+// It serves prometheus.DefaultGatherer if the default provider is not a PrometheusProvider.
 func PrometheusHandler() http.Handler {
-	return promhttp.InstrumentMetricHandler(
-		registerer,
-		promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}),
-	)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := unwrapProvider(DefaultProvider).(PrometheusProvider); ok {
+			p.Handler().ServeHTTP(w, r)
+			return
+		}
+		defaultGathererHandler.get(promhttp.Handler).ServeHTTP(w, r)
+	})
 }
 
 func sanitizeName(name string) string {
@@ -5,8 +5,15 @@
 package metrics
 
 import (
-	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSanitizeName(t *testing.T) {
@@ -33,3 +40,153 @@ func TestSanitizeName(t *testing.T) {
 		})
 	}
 }
+
+// gatherHistogram returns the first histogram of the metric name.
+func gatherHistogram(t *testing.T, registry *prometheus.Registry, name string) *dto.Histogram {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetHistogram()
+		}
+	}
+	require.Failf(t, "histogram not found", name)
+	return nil
+}
+
+func TestNativeHistogram(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+
+	provider.NewNativeHistogram("native", "help", []float64{1, 2}, NativeHistogramOpts{ZeroThreshold: 0.001}).
+		With(map[string]string{}).Observe(0.0005)
+	provider.NewNativeHistogram("native_classic", "help", []float64{1, 2}, NativeHistogramOpts{BucketFactor: 1.5, ClassicBuckets: true}).
+		With(map[string]string{}).Observe(0.0005)
+
+	native := gatherHistogram(t, registry, "native")
+	assert.Empty(t, native.GetBucket())
+	assert.Equal(t, 0.001, native.GetZeroThreshold())
+	assert.Equal(t, uint64(1), native.GetZeroCount())
+	assert.Equal(t, int32(3), native.GetSchema())
+
+	classic := gatherHistogram(t, registry, "native_classic")
+	assert.Len(t, classic.GetBucket(), 2)
+	assert.Equal(t, int32(1), classic.GetSchema())
+	assert.NotEmpty(t, classic.GetPositiveSpan())
+}
+
+func TestNativeHistogramProviderOpts(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{
+		Registerer:       registry,
+		Gatherer:         registry,
+		NativeHistograms: &NativeHistogramOpts{},
+	})
+
+	provider.NewHistogram("latency", "help", nil).With(map[string]string{}).Observe(0.1)
+
+	histogram := gatherHistogram(t, registry, "latency")
+	assert.Empty(t, histogram.GetBucket())
+	assert.NotEmpty(t, histogram.GetPositiveSpan())
+}
+
+func TestMetricOptsFullName(t *testing.T) {
+	assert.Equal(t, "latency", MetricOpts{}.FullName("latency"))
+	assert.Equal(t, "ab_bans_latency_seconds", MetricOpts{Namespace: "ab", Subsystem: "bans", Unit: "seconds"}.FullName("latency"))
+	assert.Equal(t, "ab_latency_seconds", MetricOpts{Namespace: "ab", Unit: "seconds"}.FullName("latency_seconds"))
+}
+
+func TestSummaryWithOpts(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+
+	summary := provider.NewSummaryWithOpts("latency", "help", MetricOpts{
+		Namespace:   "ab",
+		Subsystem:   "bans",
+		Unit:        "seconds",
+		ConstLabels: map[string]string{"region": "us"},
+		Objectives:  map[float64]float64{0.99: 0.001},
+		MaxAge:      time.Minute,
+		AgeBuckets:  3,
+	}, "method")
+	summary.With(map[string]string{"method": "GET"}).Observe(0.5)
+	provider.NewSummary("default_objectives", "help").With(map[string]string{}).Observe(1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	got := map[string]*dto.Metric{}
+	for _, family := range families {
+		got[family.GetName()] = family.GetMetric()[0]
+	}
+
+	metric := got["ab_bans_latency_seconds"]
+	require.NotNil(t, metric)
+	assert.Len(t, metric.GetLabel(), 2)
+	require.Len(t, metric.GetSummary().GetQuantile(), 1)
+	assert.Equal(t, 0.99, metric.GetSummary().GetQuantile()[0].GetQuantile())
+	assert.Equal(t, 0.5, metric.GetSummary().GetQuantile()[0].GetValue())
+
+	assert.Len(t, got["default_objectives"].GetSummary().GetQuantile(), len(DefaultObjectives))
+}
+
+func TestPrometheusProviderOwnsRegistry(t *testing.T) {
+	providerA := NewPrometheusProvider(PrometheusProviderOpts{Registerer: prometheus.NewRegistry()})
+	providerB := NewPrometheusProvider(PrometheusProviderOpts{
+		Registerer:                prometheus.NewRegistry(),
+		DisableGoCollector:        true,
+		DisableProcessCollector:   true,
+		DisableBuildInfoCollector: true,
+	})
+
+	require.NotPanics(t, func() {
+		providerA.NewCounter("requests", "requests").With(map[string]string{}).Inc()
+		providerB.NewCounter("requests", "requests", "code").With(map[string]string{"code": "200"}).Inc()
+	})
+
+	namesA := gatherNames(t, providerA.gatherer)
+	assert.Contains(t, namesA, "requests")
+	assert.Contains(t, namesA, "go_goroutines")
+	assert.Contains(t, namesA, "go_build_info")
+	assert.Equal(t, []string{"requests"}, gatherNames(t, providerB.gatherer))
+}
+
+func TestPrometheusProviderHandler(t *testing.T) {
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: prometheus.NewRegistry(), DisableGoCollector: true})
+	provider.NewCounter("requests", "requests").With(map[string]string{}).Inc()
+
+	rec := httptest.NewRecorder()
+	provider.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "requests 1")
+	assert.NotContains(t, rec.Body.String(), "go_goroutines")
+}
+
+func gatherNames(t *testing.T, gatherer prometheus.Gatherer) []string {
+	families, err := gatherer.Gather()
+	require.NoError(t, err)
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	return names
+}
+
+func TestPrometheusProviderDefaultRegisterer(t *testing.T) {
+	provider := NewPrometheusProvider(PrometheusProviderOpts{})
+
+	assert.Equal(t, prometheus.DefaultRegisterer, provider.registerer)
+	assert.Equal(t, prometheus.DefaultGatherer, provider.gatherer)
+}
+
+func TestPrometheusHandlerUnwrapsProvider(t *testing.T) {
+	defer SetProvider(DefaultProvider)
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: prometheus.NewRegistry()})
+	SetProvider(NewCardinalityLimiter(provider, CardinalityLimiterOpts{}))
+	CounterVec("limited_requests", "requests", []string{"code"}).With(map[string]string{"code": "200"}).Inc()
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Contains(t, rec.Body.String(), `limited_requests{code="200"} 1`)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegistrationError is reported to the error handler of the provider when a metric conflicts with
+// an already registered one, e.g. same name with other help, labels or type, and is returned by
+// MetricRegistrationError for the created metric. The created metric still records its values, but it
+// is not exported. A histogram registered again with other buckets records into the existing one,
+// with its buckets.
+type RegistrationError struct {
+	Metric string
+	Err    error
+}
+
+func (e *RegistrationError) Error() string {
+	return fmt.Sprintf("metric %s cannot be registered: %v", e.Metric, e.Err)
+}
+
+func (e *RegistrationError) Unwrap() error {
+	return e.Err
+}
+
+// UnregisterProvider represents a metric provider able to unregister its metrics, i.e. Prometheus.
+type UnregisterProvider interface {
+	Provider
+	Unregister(name string) bool
+}
+
+// Unregister unregisters the metric name from the default provider, so that it is not exported anymore.
+// It returns false if there is no such metric or if the default provider is not an UnregisterProvider.
+func Unregister(name string) bool {
+	if p, ok := DefaultProvider.(UnregisterProvider); ok {
+		return p.Unregister(name)
+	}
+	return false
+}
+
+// MetricRegistrationError returns the *RegistrationError of metric, a metric created by a PrometheusProvider,
+// if it conflicted with an already registered metric, or nil.
+func MetricRegistrationError(metric interface{}) error {
+	if m, ok := metric.(registeredMetric); ok {
+		return m.registrationError()
+	}
+	return nil
+}
+
+// registeredMetric is implemented by the metrics of PrometheusProvider to expose their registration error.
+type registeredMetric interface {
+	registrationError() error
+}
+
+// collectorSet tracks the collectors registered by a provider by metric name, to unregister them,
+// and the buckets of its histograms, to detect the ones registered again with other buckets.
+type collectorSet struct {
+	mu         sync.Mutex
+	collectors map[string]prometheus.Collector
+	buckets    map[string][]float64
+}
+
+func newCollectorSet() *collectorSet {
+	return &collectorSet{collectors: map[string]prometheus.Collector{}, buckets: map[string][]float64{}}
+}
+
+func (s *collectorSet) add(name string, collector prometheus.Collector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collectors[name] = collector
+}
+
+func (s *collectorSet) remove(name string) (prometheus.Collector, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	collector, ok := s.collectors[name]
+	delete(s.collectors, name)
+	delete(s.buckets, name)
+	return collector, ok
+}
+
+// addBuckets records the buckets of the histogram name, and returns the buckets it was first registered with.
+func (s *collectorSet) addBuckets(name string, buckets []float64) []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.buckets[name]; ok {
+		return existing
+	}
+	s.buckets[name] = buckets
+	return buckets
+}
+
+// register registers collector as the metric name and returns it, or returns the collector already registered
+// with the same name, help and labels. A conflicting metric is reported to the error handler and returned as
+// a *RegistrationError, with collector returned unregistered.
+func (p PrometheusProvider) register(name string, collector prometheus.Collector) (prometheus.Collector, error) {
+	err := p.registerer.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	switch {
+	case err == nil:
+	case errors.As(err, &alreadyRegistered):
+		collector = alreadyRegistered.ExistingCollector
+	default:
+		return collector, p.registrationError(name, err)
+	}
+
+	if p.collectors != nil {
+		p.collectors.add(name, collector)
+	}
+	return collector, nil
+}
+
+// registerObservable registers collector as the metric name, replacing the collector already registered with
+// the same name, help and labels, so that the metric reads the latest callback, e.g. the one of a reopened *sql.DB.
+func (p PrometheusProvider) registerObservable(name string, collector *observableCollector) {
+	err := p.registerer.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		p.registerer.Unregister(alreadyRegistered.ExistingCollector)
+		err = p.registerer.Register(collector)
+	}
+	if err != nil {
+		p.registrationError(name, err)
+		return
+	}
+
+	if p.collectors != nil {
+		p.collectors.add(name, collector)
+	}
+}
+
+// registerVec registers vec as the metric name, returning the existing vec of the same type if any.
+func registerVec[V prometheus.Collector](p PrometheusProvider, name string, vec V) (V, error) {
+	collector, err := p.register(name, vec)
+	if err != nil {
+		return vec, err
+	}
+	existing, ok := collector.(V)
+	if !ok {
+		return vec, p.registrationError(name, fmt.Errorf("already registered as %T", collector))
+	}
+	return existing, nil
+}
+
+// checkBuckets reports the histogram name registered again by the provider with other buckets than the first time.
+func (p PrometheusProvider) checkBuckets(name string, buckets []float64) error {
+	if p.collectors == nil {
+		return nil
+	}
+	if existing := p.collectors.addBuckets(name, buckets); !slices.Equal(existing, buckets) {
+		return p.registrationError(name, fmt.Errorf("already registered with buckets %v instead of %v", existing, buckets))
+	}
+	return nil
+}
+
+// Unregister unregisters the metric name created with the provider, so that it is not exported anymore,
+// e.g. when a dynamic component is stopped. It returns false if there is no such metric.
+func (p PrometheusProvider) Unregister(name string) bool {
+	if p.collectors == nil {
+		return false
+	}
+	collector, ok := p.collectors.remove(sanitizeName(name))
+	if !ok {
+		return false
+	}
+	return p.registerer.Unregister(collector)
+}
+
+// registrationError reports the registration error of the metric name to the error handler and returns it.
+func (p PrometheusProvider) registrationError(name string, err error) error {
+	registrationErr := &RegistrationError{Metric: name, Err: err}
+	p.reportError(registrationErr)
+	return registrationErr
+}
+
+func (p PrometheusProvider) reportError(err error) {
+	if p.errorHandler != nil {
+		p.errorHandler(err)
+	}
+}
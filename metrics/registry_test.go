@@ -0,0 +1,94 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusProviderReturnsExistingMetric(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+
+	var second CounterVecMetric
+	require.NotPanics(t, func() {
+		provider.NewCounter("requests", "requests", "code").With(map[string]string{"code": "200"}).Inc()
+		second = provider.NewCounter("requests", "requests", "code")
+	})
+	second.With(map[string]string{"code": "200"}).Inc()
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(second.(counterVec).WithLabelValues("200")))
+}
+
+func TestPrometheusProviderReportsConflictingMetric(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	var reported []error
+	provider := NewPrometheusProvider(PrometheusProviderOpts{
+		Registerer:   registry,
+		Gatherer:     registry,
+		ErrorHandler: func(err error) { reported = append(reported, err) },
+	})
+
+	assert.NoError(t, MetricRegistrationError(provider.NewCounter("requests", "requests", "code")))
+	assert.NoError(t, MetricRegistrationError(provider.NewCounter("requests", "requests", "code")))
+	var conflicting []error
+	require.NotPanics(t, func() {
+		counter := provider.NewCounter("requests", "requests", "method")
+		counter.With(map[string]string{"method": "GET"}).Inc()
+		gauge := NewCardinalityLimiter(provider, CardinalityLimiterOpts{}).NewGauge("requests", "requests", "code")
+		gauge.With(map[string]string{"code": "200"}).Set(1)
+		histogram := provider.NewHistogram("latency", "latency", []float64{1, 2})
+		assert.NoError(t, MetricRegistrationError(histogram))
+		histogram = provider.NewHistogram("latency", "latency", []float64{1, 5})
+		conflicting = []error{MetricRegistrationError(counter), MetricRegistrationError(gauge), MetricRegistrationError(histogram)}
+	})
+
+	require.Len(t, reported, 3)
+	assert.Equal(t, reported, conflicting)
+	var registrationErr *RegistrationError
+	for i, metric := range []string{"requests", "requests", "latency"} {
+		require.True(t, errors.As(conflicting[i], &registrationErr))
+		assert.Equal(t, metric, registrationErr.Metric)
+	}
+	assert.EqualError(t, conflicting[2], "metric latency cannot be registered: already registered with buckets [1 2] instead of [1 5]")
+}
+
+func TestPrometheusProviderUnregister(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+
+	provider.NewGauge("queue-size", "queue size").With(map[string]string{}).Set(1)
+	provider.NewObservableGauge("workers", "workers", func() []Observation {
+		return []Observation{{Value: 1}}
+	})
+	assert.Equal(t, 2, testutil.CollectAndCount(registry, "queue_size", "workers"))
+
+	assert.True(t, provider.Unregister("queue-size"))
+	assert.True(t, provider.Unregister("workers"))
+	assert.False(t, provider.Unregister("workers"))
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "queue_size", "workers"))
+
+	provider.NewGauge("queue_size", "queue size").With(map[string]string{}).Set(2)
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "queue_size"))
+}
+
+func TestClientsShareRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+
+	assert.NotPanics(t, func() {
+		NewClient("test", BuildInfo{}, &Opts{Provider: provider}).NewDBMetrics("bans").NewCall("get_ban").CallEnded()
+		NewClient("test", BuildInfo{}, &Opts{Provider: provider}).NewDBMetrics("bans").NewCall("get_ban").CallEnded()
+	})
+
+	histogram := gatherHistogram(t, registry, "ab_test_bans_db_latency_seconds")
+	assert.Equal(t, uint64(2), histogram.GetSampleCount())
+}
@@ -11,19 +11,35 @@ import (
 	"github.com/emicklei/go-restful/v3"
 )
 
+// RestfulFilter returns a filter that records the HTTP metrics with the default client set up by Initialize.
+// The trace ID of the request span is attached as exemplar when the span is sampled.
+// Requests are not recorded before Initialize is called.
 func RestfulFilter() restful.FilterFunction {
 	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
-		dateStart := time.Now()
-		Namespace := req.PathParameter(namespacePathParameter)
-		chain.ProcessFilter(req, resp)
-		reqSelectedRoot := req.SelectedRoute()
-		if reqSelectedRoot != nil {
-			httpMetrics.With(map[string]string{
-				labelNamespace:    Namespace,
-				labelPath:         reqSelectedRoot.Path(),
-				labelMethod:       reqSelectedRoot.Method(),
-				labelResponseCode: strconv.Itoa(resp.StatusCode()),
-			}).Observe(time.Since(dateStart).Seconds())
+		if !defaultClient.initialized() {
+			chain.ProcessFilter(req, resp)
+			return
 		}
+		defaultClient.processFilter(req, resp, chain)
+	}
+}
+
+// RestfulFilter returns a filter that records the HTTP metrics with the client provider.
+func (c *Client) RestfulFilter() restful.FilterFunction {
+	return c.processFilter
+}
+
+func (c *Client) processFilter(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+	dateStart := time.Now()
+	Namespace := req.PathParameter(c.namespacePathParameter)
+	chain.ProcessFilter(req, resp)
+	reqSelectedRoot := req.SelectedRoute()
+	if reqSelectedRoot != nil {
+		observeWithExemplar(req.Request.Context(), c.httpMetrics.With(map[string]string{
+			labelNamespace:    Namespace,
+			labelPath:         reqSelectedRoot.Path(),
+			labelMethod:       reqSelectedRoot.Method(),
+			labelResponseCode: strconv.Itoa(resp.StatusCode()),
+		}), time.Since(dateStart).Seconds())
 	}
 }
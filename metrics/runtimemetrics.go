@@ -5,33 +5,93 @@
 package metrics
 
 import (
+	"math"
 	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
-)
 
-var (
-	runtimeMetricsGaugeMap  map[string]GaugeMetric
-	runtimeMetricsHistogram map[string]ObserverMetric
+	"github.com/prometheus/client_golang/prometheus"
 )
 
-func sendRuntimeMetrics() {
-	ticker := time.NewTicker(2 * time.Second)
-	quit := make(chan struct{})
-	defer func() {
-		ticker.Stop()
-	}()
+const runtimeMetricsInterval = 2 * time.Second
 
-	for {
-		select {
-		case <-ticker.C:
+// startRuntimeMetrics sends the runtime/metrics values to the client provider: the scalar ones as gauges
+// every runtimeMetricsInterval, and the histograms as Prometheus const histograms read at scrape time.
+// The histograms cannot be replayed on a generic ObserverMetric, so with a provider that does not wrap
+// a PrometheusProvider they are sent as gauges of their runtimeHistogramQuantiles instead.
+func (c *Client) startRuntimeMetrics() {
+	c.runtimeMetricsGaugeMap = make(map[string]GaugeMetric)
+	c.runtimeMetricsQuantileMap = make(map[string]GaugeVecMetric)
+
+	prometheusProvider, isPrometheus := unwrapProvider(c.provider).(PrometheusProvider)
+	var histogramNames []string
+	for _, desc := range metrics.All() {
+		name := generateMetricsName(genericServiceName, desc.Name)
+		switch desc.Kind {
+		case metrics.KindUint64, metrics.KindFloat64:
+			c.runtimeMetricsGaugeMap[desc.Name] = c.provider.NewGauge(name, desc.Description).With(map[string]string{})
+		case metrics.KindFloat64Histogram:
+			if isPrometheus {
+				histogramNames = append(histogramNames, desc.Name)
+			} else {
+				c.runtimeMetricsQuantileMap[desc.Name] = c.provider.NewGauge(name, desc.Description, labelQuantile)
+			}
+		}
+	}
 
-			descs := metrics.All()
+	var collector *runtimeHistogramCollector
+	if isPrometheus {
+		collector = newRuntimeHistogramCollector(histogramNames)
+		// the histograms are already exported if another client shares the registry
+		if err := prometheusProvider.registerer.Register(collector); err != nil {
+			collector = nil
+		}
+	}
 
-			samples := make([]metrics.Sample, len(descs))
-			for i := range samples {
-				samples[i].Name = descs[i].Name
+	quit := make(chan struct{})
+	var once sync.Once
+	c.stopRuntimeMetrics = func() {
+		once.Do(func() {
+			close(quit)
+			if collector != nil {
+				prometheusProvider.registerer.Unregister(collector)
 			}
+		})
+	}
+
+	go c.sendRuntimeMetrics(quit)
+}
+
+// StopRuntimeMetrics stops sending the runtime metrics of the client and unregisters its runtime histograms.
+// It is safe to call it several times or when the runtime metrics are disabled.
+func (c *Client) StopRuntimeMetrics() {
+	if c.stopRuntimeMetrics != nil {
+		c.stopRuntimeMetrics()
+	}
+}
 
+// StopRuntimeMetrics stops the runtime metrics of the default client set up by Initialize.
+func StopRuntimeMetrics() {
+	defaultClient.StopRuntimeMetrics()
+}
+
+func (c *Client) sendRuntimeMetrics(quit <-chan struct{}) {
+	ticker := time.NewTicker(runtimeMetricsInterval)
+	defer ticker.Stop()
+
+	samples := make([]metrics.Sample, 0, len(c.runtimeMetricsGaugeMap)+len(c.runtimeMetricsQuantileMap))
+	for name := range c.runtimeMetricsGaugeMap {
+		samples = append(samples, metrics.Sample{Name: name})
+	}
+	for name := range c.runtimeMetricsQuantileMap {
+		samples = append(samples, metrics.Sample{Name: name})
+	}
+
+	for {
+		select {
+		case <-ticker.C:
 			metrics.Read(samples)
 
 			for _, sample := range samples {
@@ -39,14 +99,16 @@ func sendRuntimeMetrics() {
 
 				switch value.Kind() {
 				case metrics.KindUint64:
-					runtimeMetricsGaugeMap[name].Set(float64(value.Uint64()))
+					c.runtimeMetricsGaugeMap[name].Set(float64(value.Uint64()))
 
 				case metrics.KindFloat64:
-					runtimeMetricsGaugeMap[name].Set(value.Float64())
+					c.runtimeMetricsGaugeMap[name].Set(value.Float64())
 
 				case metrics.KindFloat64Histogram:
-					for _, i := range value.Float64Histogram().Buckets {
-						runtimeMetricsHistogram[name].Observe(i)
+					histogram := value.Float64Histogram()
+					for _, q := range runtimeHistogramQuantiles {
+						c.runtimeMetricsQuantileMap[name].With(map[string]string{labelQuantile: strconv.FormatFloat(q, 'g', -1, 64)}).
+							Set(histogramQuantile(histogram, q))
 					}
 				}
 			}
@@ -56,3 +118,146 @@ func sendRuntimeMetrics() {
 		}
 	}
 }
+
+// runtimeHistogramQuantiles are the quantiles of the runtime histograms sent as gauges.
+var runtimeHistogramQuantiles = []float64{0.5, 0.9, 0.99}
+
+// histogramQuantile estimates the q quantile of a runtime/metrics histogram from the midpoint of its bucket.
+func histogramQuantile(h *metrics.Float64Histogram, q float64) float64 {
+	var total uint64
+	for _, n := range h.Counts {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(total)))
+	var count uint64
+	for i, n := range h.Counts {
+		count += n
+		if count >= rank && n > 0 {
+			return bucketMidpoint(h.Buckets[i], h.Buckets[i+1])
+		}
+	}
+	return bucketMidpoint(h.Buckets[len(h.Buckets)-2], h.Buckets[len(h.Buckets)-1])
+}
+
+// runtimeHistogramCollector represents an internal collector that exports the runtime/metrics histograms
+// as const histograms with their real bucket counts
+type runtimeHistogramCollector struct {
+	names []string
+	descs []*prometheus.Desc
+}
+
+func newRuntimeHistogramCollector(names []string) *runtimeHistogramCollector {
+	descriptions := map[string]string{}
+	for _, desc := range metrics.All() {
+		descriptions[desc.Name] = desc.Description
+	}
+
+	c := &runtimeHistogramCollector{names: names}
+	for _, name := range names {
+		c.descs = append(c.descs, prometheus.NewDesc(
+			sanitizeName(generateMetricsName(genericServiceName, name)), descriptions[name], nil, nil,
+		))
+	}
+	return c
+}
+
+func (c *runtimeHistogramCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+func (c *runtimeHistogramCollector) Collect(ch chan<- prometheus.Metric) {
+	samples := make([]metrics.Sample, len(c.names))
+	for i, name := range c.names {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+
+	for i, sample := range samples {
+		if sample.Value.Kind() != metrics.KindFloat64Histogram {
+			continue
+		}
+		count, sum, buckets := runtimeHistogramToPrometheus(sample.Value.Float64Histogram(), runtimeBucketFactor(sample.Name))
+		metric, err := prometheus.NewConstHistogram(c.descs[i], count, sum, buckets)
+		if err != nil {
+			metric = prometheus.NewInvalidMetric(c.descs[i], err)
+		}
+		ch <- metric
+	}
+}
+
+// runtimeBucketFactor returns the minimum ratio between two exported bucket boundaries for the runtime metric unit:
+// a bucket per power of 2 for bytes and per power of 10 for seconds.
+func runtimeBucketFactor(name string) float64 {
+	if strings.HasSuffix(name, ":seconds") {
+		return 10
+	}
+	return 2
+}
+
+// runtimeHistogramToPrometheus converts a runtime/metrics histogram to the cumulative Prometheus buckets,
+// coalesced by factor. Bucket i of the runtime histogram counts the values in [Buckets[i], Buckets[i+1]),
+// so its count is added to the Prometheus bucket whose upper bound is Buckets[i+1].
+// The runtime does not report the sum, it is estimated from the bucket midpoints.
+func runtimeHistogramToPrometheus(h *metrics.Float64Histogram, factor float64) (uint64, float64, map[float64]uint64) {
+	bounds := coalesceBuckets(h.Buckets, factor)
+	buckets := make(map[float64]uint64, len(bounds))
+
+	// a boundary at the lowest runtime boundary counts nothing, the runtime buckets are lower inclusive
+	next := 0
+	for next < len(bounds) && bounds[next] <= h.Buckets[0] {
+		buckets[bounds[next]] = 0
+		next++
+	}
+
+	var count uint64
+	var sum float64
+	for i, n := range h.Counts {
+		lower, upper := h.Buckets[i], h.Buckets[i+1]
+		count += n
+		if n > 0 {
+			sum += float64(n) * bucketMidpoint(lower, upper)
+		}
+		for next < len(bounds) && bounds[next] <= upper {
+			buckets[bounds[next]] = count
+			next++
+		}
+	}
+	return count, sum, buckets
+}
+
+// coalesceBuckets keeps the finite boundaries at least factor times greater than the previous kept one,
+// so that each exported bucket matches a boundary of the runtime histogram.
+func coalesceBuckets(boundaries []float64, factor float64) []float64 {
+	var result []float64
+	for _, b := range boundaries {
+		if math.IsInf(b, 0) {
+			continue
+		}
+		if len(result) == 0 {
+			result = append(result, b)
+			continue
+		}
+		last := result[len(result)-1]
+		if (last <= 0 && b > last) || (last > 0 && b >= last*factor) {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+func bucketMidpoint(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	default:
+		return (lower + upper) / 2
+	}
+}
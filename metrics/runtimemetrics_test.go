@@ -0,0 +1,109 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeHistogramCollector(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider, EnableRuntimeMetrics: true})
+	defer client.StopRuntimeMetrics()
+
+	runtime.GC()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	found := false
+	for _, family := range families {
+		if family.GetName() != "ab_service__gc_pauses:seconds" {
+			continue
+		}
+		found = true
+		h := family.GetMetric()[0].GetHistogram()
+		assert.Positive(t, h.GetSampleCount())
+		assert.LessOrEqual(t, len(h.GetBucket()), 20)
+
+		var previous uint64
+		for _, bucket := range h.GetBucket() {
+			assert.GreaterOrEqual(t, bucket.GetCumulativeCount(), previous)
+			assert.LessOrEqual(t, bucket.GetCumulativeCount(), h.GetSampleCount())
+			previous = bucket.GetCumulativeCount()
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestStopRuntimeMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider, EnableRuntimeMetrics: true})
+
+	client.StopRuntimeMetrics()
+	client.StopRuntimeMetrics()
+
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "ab_service__gc_pauses:seconds"))
+}
+
+func TestRuntimeHistogramCollectorWrappedProvider(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	limiter := NewCardinalityLimiter(provider, CardinalityLimiterOpts{})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: limiter, EnableRuntimeMetrics: true})
+	defer client.StopRuntimeMetrics()
+
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "ab_service__gc_pauses:seconds"))
+}
+
+func TestInitializeStopsPreviousRuntimeMetrics(t *testing.T) {
+	previousProvider, previousClient := DefaultProvider, defaultClient
+	defer func() {
+		DefaultProvider, defaultClient = previousProvider, previousClient
+	}()
+
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	Initialize("test", BuildInfo{}, &Opts{Provider: provider, EnableRuntimeMetrics: true})
+	assert.Equal(t, 1, testutil.CollectAndCount(registry, "ab_service__gc_pauses:seconds"))
+
+	Initialize("test", BuildInfo{}, &Opts{Provider: NewPrometheusProvider(PrometheusProviderOpts{Registerer: prometheus.NewRegistry()})})
+	assert.Equal(t, 0, testutil.CollectAndCount(registry, "ab_service__gc_pauses:seconds"))
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{0, 5, 4, 1},
+		Buckets: []float64{math.Inf(-1), 1, 2, 4, math.Inf(1)},
+	}
+
+	assert.Equal(t, 1.5, histogramQuantile(h, 0.5))
+	assert.Equal(t, 3.0, histogramQuantile(h, 0.9))
+	assert.Equal(t, 4.0, histogramQuantile(h, 0.99))
+	assert.Equal(t, 0.0, histogramQuantile(&metrics.Float64Histogram{Counts: []uint64{0}, Buckets: []float64{0, 1}}, 0.5))
+}
+
+func TestRuntimeHistogramToPrometheus(t *testing.T) {
+	h := &metrics.Float64Histogram{
+		Counts:  []uint64{1, 2, 3, 4, 5},
+		Buckets: []float64{math.Inf(-1), 1, 2, 4, 8, math.Inf(1)},
+	}
+
+	count, sum, buckets := runtimeHistogramToPrometheus(h, 4)
+
+	assert.Equal(t, uint64(15), count)
+	assert.Equal(t, 1*1+2*1.5+3*3+4*6+5*8.0, sum)
+	assert.Equal(t, map[float64]uint64{1: 1, 4: 6}, buckets)
+}
@@ -29,10 +29,24 @@ func NewWebService(basePath string) *serviceBuilder {
 }
 
 func (s *serviceBuilder) MetricsRoute(metricsHandler http.Handler) *serviceBuilder {
+	return s.handlerRoute("/metrics", metricsHandler)
+}
+
+// HealthRoute adds the GET /healthz liveness route, reporting the checks added with HealthCheckOpts.Liveness.
+func (s *serviceBuilder) HealthRoute(checks *HealthChecks) *serviceBuilder {
+	return s.handlerRoute("/healthz", checks.LivenessHandler())
+}
+
+// ReadinessRoute adds the GET /readyz readiness route, reporting every check of checks.
+func (s *serviceBuilder) ReadinessRoute(checks *HealthChecks) *serviceBuilder {
+	return s.handlerRoute("/readyz", checks.ReadinessHandler())
+}
+
+func (s *serviceBuilder) handlerRoute(path string, handler http.Handler) *serviceBuilder {
 	s.webService.Route(s.webService.
-		GET("/metrics").
+		GET(path).
 		To(func(req *restful.Request, res *restful.Response) {
-			metricsHandler.ServeHTTP(res.ResponseWriter, req.Request)
+			handler.ServeHTTP(res.ResponseWriter, req.Request)
 		}))
 
 	return s
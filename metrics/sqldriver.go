@@ -0,0 +1,425 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	sqlTracerName = "github.com/AccelByte/observability-go-sdk/metrics"
+
+	sqlActionPrepare  = "prepare"
+	sqlActionExec     = "exec"
+	sqlActionQuery    = "query"
+	sqlActionBegin    = "begin"
+	sqlActionCommit   = "commit"
+	sqlActionRollback = "rollback"
+)
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlDollarQuote    = regexp.MustCompile(`\$(?:[A-Za-z_]\w*)?\$`)
+	sqlNumericLiteral = regexp.MustCompile(`([^\w$.?]|^)-?\d+(?:\.\d+)?`)
+)
+
+// OpenDB opens a *sql.DB with the registered driverName driver wrapped by WrapDriver.
+func (d *DBMetrics) OpenDB(driverName, dataSourceName string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	if err = db.Close(); err != nil {
+		return nil, err
+	}
+
+	wrapped := d.WrapDriver(drv)
+	if driverCtx, ok := wrapped.(driver.DriverContext); ok {
+		connector, err := driverCtx.OpenConnector(dataSourceName)
+		if err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(connector), nil
+	}
+	return sql.OpenDB(dsnConnector{dsn: dataSourceName, driver: wrapped}), nil
+}
+
+// WrapDriver wraps drv so the prepare, exec, query, begin, commit and rollback calls are timed
+// in the DB latency histogram, labelled with the action and the result from the returned error.
+// When the context holds a span, a child span with the sanitized statement is also started.
+// The extra labels passed to NewDBMetrics are recorded with empty values.
+func (d *DBMetrics) WrapDriver(drv driver.Driver) driver.Driver {
+	if driverCtx, ok := drv.(driver.DriverContext); ok {
+		return &sqlDriverContext{sqlDriver{Driver: drv, metrics: d}, driverCtx}
+	}
+	return &sqlDriver{Driver: drv, metrics: d}
+}
+
+// observe times f and records it as a DB call, driver.ErrSkip is not recorded since database/sql
+// falls back to another (recorded) path.
+func (d *DBMetrics) observe(ctx context.Context, action, query string, f func() error) error {
+	start := time.Now()
+	call := d.NewCallWithContext(ctx, action)
+	err := f()
+	if errors.Is(err, driver.ErrSkip) {
+		return err
+	}
+
+	labels := make(map[string]string, len(d.labels))
+	for _, label := range d.labels {
+		labels[label] = ""
+	}
+	call.WithLabel(labels)
+	if err != nil {
+		call.Error()
+	}
+	call.CallEnded()
+
+	d.traceCall(ctx, action, query, start, err)
+	return err
+}
+
+// traceCall records the DB call as a child span of the span in ctx, if any. The span is created
+// once the call is over so the calls skipped with driver.ErrSkip are not traced. It is created with the
+// tracer provider of the span in ctx, e.g. the one of the trace client which started it.
+func (d *DBMetrics) traceCall(ctx context.Context, action, query string, start time.Time, err error) {
+	if !oteltrace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+
+	attrs := []attribute.KeyValue{semconv.DBName(d.dbName), semconv.DBOperation(action)}
+	if query != "" {
+		attrs = append(attrs, semconv.DBStatement(SanitizeSQL(query)))
+	}
+	tracer := oteltrace.SpanFromContext(ctx).TracerProvider().Tracer(sqlTracerName)
+	_, span := tracer.Start(ctx, fmt.Sprintf("%s.%s", d.dbName, action),
+		oteltrace.WithTimestamp(start),
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(attrs...))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SanitizeSQL replaces the single-quoted, dollar-quoted and numeric literals of query with '?'
+// so no user data is exported. The double-quoted identifiers, e.g. "user", are kept.
+func SanitizeSQL(query string) string {
+	query = sanitizeDollarQuotes(query)
+	query = sqlStringLiteral.ReplaceAllString(query, "?")
+	return sqlNumericLiteral.ReplaceAllString(query, "${1}?")
+}
+
+// sanitizeDollarQuotes replaces the PostgreSQL dollar-quoted literals of query, e.g. $$text$$ or $tag$text$tag$,
+// with '?'. An unterminated literal is replaced up to the end of query.
+func sanitizeDollarQuotes(query string) string {
+	var b strings.Builder
+	for {
+		loc := sqlDollarQuote.FindStringIndex(query)
+		if loc == nil {
+			b.WriteString(query)
+			return b.String()
+		}
+		b.WriteString(query[:loc[0]])
+		b.WriteString("?")
+		tag := query[loc[0]:loc[1]]
+		end := strings.Index(query[loc[1]:], tag)
+		if end < 0 {
+			return b.String()
+		}
+		query = query[loc[1]+end+len(tag):]
+	}
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type sqlDriver struct {
+	driver.Driver
+	metrics *DBMetrics
+}
+
+func (d *sqlDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{Conn: conn, metrics: d.metrics}, nil
+}
+
+type sqlDriverContext struct {
+	sqlDriver
+	driverCtx driver.DriverContext
+}
+
+func (d *sqlDriverContext) OpenConnector(name string) (driver.Connector, error) {
+	connector, err := d.driverCtx.OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConnector{Connector: connector, driver: d}, nil
+}
+
+type sqlConnector struct {
+	driver.Connector
+	driver *sqlDriverContext
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{Conn: conn, metrics: c.driver.metrics}, nil
+}
+
+func (c *sqlConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// sqlConn wraps a driver.Conn, every optional interface falls back to the database/sql default
+// behaviour when the wrapped connection does not implement it.
+type sqlConn struct {
+	driver.Conn
+	metrics *DBMetrics
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	err := c.metrics.observe(ctx, sqlActionPrepare, query, func() (err error) {
+		if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+			stmt, err = preparer.PrepareContext(ctx, query)
+		} else {
+			stmt, err = c.Conn.Prepare(query)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sqlStmt{Stmt: stmt, conn: c, query: query, metrics: c.metrics}, nil
+}
+
+func (c *sqlConn) Begin() (driver.Tx, error) { // nolint:staticcheck
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	err := c.metrics.observe(ctx, sqlActionBegin, "", func() (err error) {
+		if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+			tx, err = beginner.BeginTx(ctx, opts)
+			return err
+		}
+		// same checks as database/sql for the drivers without BeginTx
+		if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+			return errors.New("sql: driver does not support non-default isolation level")
+		}
+		if opts.ReadOnly {
+			return errors.New("sql: driver does not support read-only transactions")
+		}
+		tx, err = c.Conn.Begin() // nolint:staticcheck
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{Tx: tx, ctx: ctx, metrics: c.metrics}, nil
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var result driver.Result
+	err := c.metrics.observe(ctx, sqlActionExec, query, func() (err error) {
+		switch execer := c.Conn.(type) {
+		case driver.ExecerContext:
+			result, err = execer.ExecContext(ctx, query, args)
+		case driver.Execer: // nolint:staticcheck
+			var values []driver.Value
+			if values, err = namedValuesToValues(args); err == nil {
+				result, err = execer.Exec(query, values)
+			}
+		default:
+			err = driver.ErrSkip
+		}
+		return err
+	})
+	return result, err
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := c.metrics.observe(ctx, sqlActionQuery, query, func() (err error) {
+		switch queryer := c.Conn.(type) {
+		case driver.QueryerContext:
+			rows, err = queryer.QueryContext(ctx, query, args)
+		case driver.Queryer: // nolint:staticcheck
+			var values []driver.Value
+			if values, err = namedValuesToValues(args); err == nil {
+				rows, err = queryer.Query(query, values)
+			}
+		default:
+			err = driver.ErrSkip
+		}
+		return err
+	})
+	return rows, err
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *sqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type sqlStmt struct {
+	driver.Stmt
+	conn    *sqlConn
+	query   string
+	metrics *DBMetrics
+}
+
+func (s *sqlStmt) Exec(args []driver.Value) (driver.Result, error) { // nolint:staticcheck
+	return s.ExecContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	var result driver.Result
+	err := s.metrics.observe(ctx, sqlActionExec, s.query, func() (err error) {
+		if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+			result, err = execer.ExecContext(ctx, args)
+			return err
+		}
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			result, err = s.Stmt.Exec(values) // nolint:staticcheck
+		}
+		return err
+	})
+	return result, err
+}
+
+func (s *sqlStmt) Query(args []driver.Value) (driver.Rows, error) { // nolint:staticcheck
+	return s.QueryContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var rows driver.Rows
+	err := s.metrics.observe(ctx, sqlActionQuery, s.query, func() (err error) {
+		if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+			rows, err = queryer.QueryContext(ctx, args)
+			return err
+		}
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values) // nolint:staticcheck
+		}
+		return err
+	})
+	return rows, err
+}
+
+// CheckNamedValue falls back to the connection checker, as database/sql does for unwrapped statements.
+func (s *sqlStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+// ColumnConverter returns the converter of the wrapped statement, or the default one which database/sql
+// uses for the statements that are not a driver.ColumnConverter.
+func (s *sqlStmt) ColumnConverter(idx int) driver.ValueConverter { // nolint:staticcheck
+	if converter, ok := s.Stmt.(driver.ColumnConverter); ok { // nolint:staticcheck
+		return converter.ColumnConverter(idx)
+	}
+	return driver.DefaultParameterConverter
+}
+
+type sqlTx struct {
+	driver.Tx
+	ctx     context.Context
+	metrics *DBMetrics
+}
+
+func (t *sqlTx) Commit() error {
+	return t.metrics.observe(t.ctx, sqlActionCommit, "", func() error {
+		return t.Tx.Commit()
+	})
+}
+
+func (t *sqlTx) Rollback() error {
+	return t.metrics.observe(t.ctx, sqlActionRollback, "", func() error {
+		return t.Tx.Rollback()
+	})
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
+	namedValues := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		namedValues[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return namedValues
+}
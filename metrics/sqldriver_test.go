@@ -0,0 +1,161 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var errFakeQuery = errors.New("fake query failed")
+
+func init() {
+	sql.Register("metrics-fake", fakeDriver{})
+}
+
+// fakeDriver is a minimal driver without prepared statements support, failing every query.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+func (fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return nil, errFakeQuery
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func TestOpenDB(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	dbMetrics := newDBMetrics(provider, "test", nil, "bans", "tenant")
+
+	db, err := dbMetrics.OpenDB("metrics-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("DELETE FROM bans WHERE id = $1", "1")
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+	_, err = db.Query("SELECT * FROM bans") // nolint:rowserrcheck
+	require.ErrorIs(t, err, errFakeQuery)
+
+	histogram := dbMetrics.latencyMetrics.(histogramVec)
+	assert.Equal(t, 4, testutil.CollectAndCount(histogram.HistogramVec))
+	for action, result := range map[string]string{
+		sqlActionBegin:  dbCallResultSuccess,
+		sqlActionExec:   dbCallResultSuccess,
+		sqlActionCommit: dbCallResultSuccess,
+		sqlActionQuery:  dbCallResultError,
+	} {
+		_, err = histogram.GetMetricWith(prometheus.Labels{
+			dbCallLabelAction: action,
+			dbCallLabelResult: result,
+			"tenant":          "",
+		})
+		assert.NoError(t, err, action)
+	}
+}
+
+func TestSanitizeSQL(t *testing.T) {
+	testCases := []struct {
+		input          string
+		expectedOutput string
+	}{
+		{
+			input:          "SELECT * FROM bans WHERE name = 'O''Brien' AND level > 10",
+			expectedOutput: "SELECT * FROM bans WHERE name = ? AND level > ?",
+		},
+		{
+			input:          "UPDATE bans2 SET score = -1.5 WHERE id = $1",
+			expectedOutput: "UPDATE bans2 SET score = ? WHERE id = $1",
+		},
+		{
+			input:          `SELECT "user", reason FROM "bans" WHERE reason = 'cheat'`,
+			expectedOutput: `SELECT "user", reason FROM "bans" WHERE reason = ?`,
+		},
+		{
+			input:          "SELECT $$it's $1$$, $tag$a $$ b$tag$ FROM bans WHERE id = $1",
+			expectedOutput: "SELECT ?, ? FROM bans WHERE id = $1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedOutput, SanitizeSQL(testCase.input))
+	}
+}
+
+func TestOpenDBTransactionOptions(t *testing.T) {
+	dbMetrics := newDBMetrics(NewPrometheusProvider(PrometheusProviderOpts{Registerer: prometheus.NewRegistry()}), "test", nil, "bans")
+	db, err := dbMetrics.OpenDB("metrics-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+	assert.EqualError(t, err, "sql: driver does not support non-default isolation level")
+	_, err = db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	assert.EqualError(t, err, "sql: driver does not support read-only transactions")
+}
+
+func TestOpenDBTracesWithSpanProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "root")
+	dbMetrics := newDBMetrics(NewPrometheusProvider(PrometheusProviderOpts{Registerer: prometheus.NewRegistry()}), "test", nil, "bans")
+	db, err := dbMetrics.OpenDB("metrics-fake", "")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "DELETE FROM bans WHERE id = 'alice'")
+	require.NoError(t, err)
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "bans.exec", spans[0].Name())
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+// converterStmt is a statement converting every argument to a string.
+type converterStmt struct {
+	driver.Stmt
+}
+
+func (converterStmt) ColumnConverter(int) driver.ValueConverter {
+	return driver.String
+}
+
+func TestSQLStmtColumnConverter(t *testing.T) {
+	value, err := (&sqlStmt{Stmt: converterStmt{}}).ColumnConverter(0).ConvertValue(1)
+	require.NoError(t, err)
+	assert.Equal(t, "1", value)
+
+	value, err = (&sqlStmt{}).ColumnConverter(0).ConvertValue(1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), value)
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const responseCodeError = "error"
+
+// Transport is an http.RoundTripper that records the latency of the outgoing requests
+// labelled by host, method and response code.
+type Transport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+// NewTransport wraps base to record the outgoing requests with the default client set up by Initialize.
+// Requests are not recorded before Initialize is called. If base is nil, http.DefaultTransport is used.
+func NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base}
+}
+
+// NewTransport wraps base to record the outgoing requests with the client provider.
+// If base is nil, http.DefaultTransport is used.
+func (c *Client) NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, client: c}
+}
+
+// RoundTrip implements http.RoundTripper. Requests failing without a response are recorded
+// with the "error" response code.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	client := t.client
+	if client == nil {
+		client = defaultClient
+	}
+	if !client.initialized() {
+		return t.base.RoundTrip(req)
+	}
+
+	dateStart := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	responseCode := responseCodeError
+	if err == nil {
+		responseCode = strconv.Itoa(resp.StatusCode)
+	}
+	client.httpClientMetrics.With(map[string]string{
+		labelHost:         req.URL.Host,
+		labelMethod:       req.Method,
+		labelResponseCode: responseCode,
+	}).Observe(time.Since(dateStart).Seconds())
+
+	return resp, err
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	provider := NewPrometheusProvider(PrometheusProviderOpts{Registerer: registry, Gatherer: registry})
+	client := NewClient("test", BuildInfo{}, &Opts{Provider: provider})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	resp, err := (&http.Client{Transport: client.NewTransport(nil)}).Post(server.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	histogram := client.httpClientMetrics.(histogramVec)
+	_, err = histogram.GetMetricWith(prometheus.Labels{
+		labelHost:         serverURL.Host,
+		labelMethod:       http.MethodPost,
+		labelResponseCode: "201",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, testutil.CollectAndCount(histogram.HistogramVec))
+}
@@ -55,10 +55,13 @@ func newServiceContainer(basePath string, authFilter *auth.Filter, h *handlers)
 		},
 	}))
 
-	// register metrics and runtime debug routes
+	// register metrics, health, readiness and runtime debug routes
+	healthChecks := metrics.NewHealthChecks(metrics.DefaultProvider)
 	container.Add(metrics.
 		NewWebService(basePath).
 		MetricsRoute(metrics.DefaultMetricsHandler).
+		HealthRoute(healthChecks).
+		ReadinessRoute(healthChecks).
 		RuntimeDebugRoute().
 		WebService())
 
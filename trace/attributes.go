@@ -7,23 +7,29 @@ package trace
 import (
 	"github.com/AccelByte/go-restful-plugins/v4/pkg/auth/iam"
 	"github.com/emicklei/go-restful/v3"
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
-// InstrumentCommonAttributes is a filter that will add span attributes for user id and flight id
+// InstrumentCommonAttributes is a filter that will add span attributes for user id and flight id.
+// The user id, flight id and namespace are also stored in the request context baggage, see ContextWithFlightID.
 //
 // Parameters
 // tracerName: tracer name
 // excludeEndpoints: consists of blacklisted endpoint name to not send tracer
 // eg. key: /healthz , key2: map of http.Method (GET, POST, DELETE, PUT) ,  value : boolean
 func InstrumentCommonAttributes(tracerName string, excludeEndpoints map[string]map[string]bool) (filterFunc restful.FilterFunction) {
+	return NewClient(tracerName, defaultClient.serviceName, nil).InstrumentCommonAttributes(excludeEndpoints)
+}
+
+// InstrumentCommonAttributes is a filter that will add span attributes for user id and flight id
+// using the client tracer. See the package level InstrumentCommonAttributes for the parameters.
+func (c *Client) InstrumentCommonAttributes(excludeEndpoints map[string]map[string]bool) (filterFunc restful.FilterFunction) {
 	return func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
 		r := req.Request
-		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx := c.Propagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 		route := req.SelectedRoutePath()
 		spanName := route
 
@@ -42,12 +48,17 @@ func InstrumentCommonAttributes(tracerName string, excludeEndpoints map[string]m
 			tokenUserID = val.(string)
 		}
 
+		namespace := req.PathParameter(namespacePathParameter)
+
 		if jwtClaims := iam.RetrieveJWTClaims(req); jwtClaims != nil {
 			// if tokenNamespace, tokenUserID or tokenClientID is empty,
 			// fallback get from jwt claims
 			if tokenUserID == "" {
 				tokenUserID = jwtClaims.Subject
 			}
+			if namespace == "" {
+				namespace = jwtClaims.Namespace
+			}
 		}
 
 		opts := []oteltrace.SpanStartOption{
@@ -61,11 +72,11 @@ func InstrumentCommonAttributes(tracerName string, excludeEndpoints map[string]m
 			opts = append(opts, oteltrace.WithAttributes(rAttr))
 		}
 
-		tracer := otel.GetTracerProvider().Tracer(
-			tracerName,
-		)
-
-		ctx, span := tracer.Start(ctx, spanName, opts...)
+		// the request identity is carried in the baggage to be stamped on the child spans and the logger
+		ctx = ContextWithFlightID(ctx, flightID)
+		ctx = ContextWithUserID(ctx, tokenUserID)
+		ctx = ContextWithNamespace(ctx, namespace)
+		ctx, span := c.Tracer().Start(ctx, spanName, opts...)
 		defer span.End()
 
 		// pass the span through the request context
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Baggage members carrying the request identity, they are also the names of the span attributes stamped by
+// the baggage span processor. They are forwarded to the downstream services when PropagatorBaggage is enabled.
+const (
+	BaggageFlightID  = "flight.id"
+	BaggageUserID    = "user.id"
+	BaggageNamespace = "namespace"
+)
+
+var baggageMembers = []string{BaggageFlightID, BaggageUserID, BaggageNamespace}
+
+// ContextWithFlightID inserts the flight id into the ctx baggage so it is stamped on the child spans, added to the
+// context logger and forwarded to the downstream services by Transport and the gRPC client interceptors.
+func ContextWithFlightID(ctx context.Context, flightID string) context.Context {
+	return contextWithBaggageMember(ctx, BaggageFlightID, flightID)
+}
+
+// FlightIDFromContext extracts the flight id from the context baggage, or an empty string if it's not found.
+func FlightIDFromContext(ctx context.Context) string {
+	return baggage.FromContext(ctx).Member(BaggageFlightID).Value()
+}
+
+// ContextWithUserID inserts the user id into the ctx baggage so it is stamped on the child spans
+// and added to the context logger.
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return contextWithBaggageMember(ctx, BaggageUserID, userID)
+}
+
+// UserIDFromContext extracts the user id from the context baggage, or an empty string if it's not found.
+func UserIDFromContext(ctx context.Context) string {
+	return baggage.FromContext(ctx).Member(BaggageUserID).Value()
+}
+
+// ContextWithNamespace inserts the namespace into the ctx baggage so it is stamped on the child spans
+// and added to the context logger.
+func ContextWithNamespace(ctx context.Context, namespace string) context.Context {
+	return contextWithBaggageMember(ctx, BaggageNamespace, namespace)
+}
+
+// NamespaceFromContext extracts the namespace from the context baggage, or an empty string if it's not found.
+func NamespaceFromContext(ctx context.Context) string {
+	return baggage.FromContext(ctx).Member(BaggageNamespace).Value()
+}
+
+// contextWithBaggageMember sets the baggage member key, empty or invalid values leave ctx untouched.
+func contextWithBaggageMember(ctx context.Context, key, value string) context.Context {
+	if value == "" {
+		return ctx
+	}
+	// the value is escaped since the baggage only accepts a subset of ASCII
+	member, err := baggage.NewMember(key, url.PathEscape(value))
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// NewBaggageSpanProcessor returns a span processor stamping the flight id, user id and namespace found in
+// the baggage of the parent context on every started span. It is registered by SetUpTracer and its variants,
+// add it to custom tracer providers with sdktrace.WithSpanProcessor.
+func NewBaggageSpanProcessor() sdktrace.SpanProcessor {
+	return baggageSpanProcessor{}
+}
+
+// baggageSpanProcessor represents an internal span processor stamping the baggage members on the spans
+type baggageSpanProcessor struct{}
+
+func (baggageSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	bag := baggage.FromContext(parent)
+	for _, key := range baggageMembers {
+		if value := bag.Member(key).Value(); value != "" {
+			s.SetAttributes(attribute.String(key, value))
+		}
+	}
+}
+
+func (baggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan)      {}
+func (baggageSpanProcessor) Shutdown(context.Context) error   { return nil }
+func (baggageSpanProcessor) ForceFlush(context.Context) error { return nil }
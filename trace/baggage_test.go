@@ -0,0 +1,67 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBaggageRoundTrip(t *testing.T) {
+	ctx := ContextWithFlightID(context.Background(), "flight 1")
+	ctx = ContextWithUserID(ctx, "user/1")
+	ctx = ContextWithNamespace(ctx, "")
+
+	assert.Equal(t, "flight 1", FlightIDFromContext(ctx))
+	assert.Equal(t, "user/1", UserIDFromContext(ctx))
+	assert.Empty(t, NamespaceFromContext(ctx))
+}
+
+func TestBaggageSpanProcessor(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(
+			sdktrace.WithSpanProcessor(NewBaggageSpanProcessor()),
+			sdktrace.WithSyncer(exporter),
+		),
+	})
+
+	ctx := ContextWithFlightID(context.Background(), "flight-1")
+	ctx = ContextWithUserID(ctx, "user-1")
+	ctx = ContextWithNamespace(ctx, "accelbyte")
+	ctx, parent := client.NewRootSpan(ctx, "parent")
+	_, child := client.NewAutoNamedChildSpan(ctx)
+	child.End()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Subset(t, span.Attributes, []attribute.KeyValue{
+			attribute.String(BaggageFlightID, "flight-1"),
+			attribute.String(BaggageUserID, "user-1"),
+			attribute.String(BaggageNamespace, "accelbyte"),
+		}, span.Name)
+	}
+}
+
+func TestLoggerFromContextBaggageFields(t *testing.T) {
+	client := NewClient("test", "test", &Opts{Logger: logrus.New()})
+
+	ctx := ContextWithFlightID(context.Background(), "flight-1")
+	ctx = ContextWithNamespace(ctx, "accelbyte")
+	entry := client.LoggerFromContext(ctx)
+
+	assert.Equal(t, "flight-1", entry.Data[LogFieldFlightID])
+	assert.Equal(t, "accelbyte", entry.Data[LogFieldNamespace])
+	assert.NotContains(t, entry.Data, LogFieldUserID)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Opts represents the trace client configuration options.
+type Opts struct {
+	TracerProvider   trace.TracerProvider          // default is the global OpenTelemetry tracer provider
+	Propagator       propagation.TextMapPropagator // default is the global OpenTelemetry propagator
+	Logger           *logrus.Logger                // default is the logrus standard logger
+	StructuredLogger Logger                        // default is Logger, e.g. NewSlogAdapter(slog.Default()) to log with slog
+	LogFieldNames    *LogFieldNames                // default is DefaultLogFieldNames
+}
+
+// Client holds the tracing state of one service: its tracer provider, tracer name and logger.
+// Several clients can live in the same binary, each with its own tracer provider.
+type Client struct {
+	tracerName       string
+	serviceName      string
+	tracerProvider   trace.TracerProvider
+	propagator       propagation.TextMapPropagator
+	logger           *logrus.Logger
+	structuredLogger Logger
+	logFieldNames    *LogFieldNames
+}
+
+// NewClient creates a new trace client. traceProvider is the name of the tracer and service is the
+// service name used to display the traces in backends.
+func NewClient(traceProvider, service string, option *Opts) *Client {
+	c := &Client{
+		tracerName:  traceProvider,
+		serviceName: service,
+	}
+	if option != nil {
+		c.tracerProvider = option.TracerProvider
+		c.propagator = option.Propagator
+		c.logger = option.Logger
+		c.structuredLogger = option.StructuredLogger
+		c.logFieldNames = option.LogFieldNames
+	}
+	return c
+}
+
+// TracerProvider returns the tracer provider used by the client.
+func (c *Client) TracerProvider() trace.TracerProvider {
+	if c.tracerProvider == nil {
+		return otel.GetTracerProvider()
+	}
+	return c.tracerProvider
+}
+
+// Propagator returns the propagator used by the client.
+func (c *Client) Propagator() propagation.TextMapPropagator {
+	if c.propagator == nil {
+		return otel.GetTextMapPropagator()
+	}
+	return c.propagator
+}
+
+// Tracer returns the tracer of the client.
+func (c *Client) Tracer() trace.Tracer {
+	return c.TracerProvider().Tracer(c.tracerName)
+}
+
+// SetUpTracer sets up a GRPC reciever for the client service name with url as the endpoint of the collector
+// and makes the client own the resulting tracer provider and propagator. Unlike the package level SetUpTracer,
+// the global tracer provider and propagator are left untouched.
+func (c *Client) SetUpTracer(ctx context.Context, url string, connectTimeout time.Duration) (func(), error) {
+	return c.SetUpTracerWithOptions(ctx, TracerOpts{Endpoint: url, Insecure: true, ConnectTimeout: connectTimeout})
+}
+
+// SetUpTracerWithOptions sets up an OTLP exporter configured by opts for the client service name
+// and makes the client own the resulting tracer provider and propagator, leaving the global ones untouched.
+func (c *Client) SetUpTracerWithOptions(ctx context.Context, opts TracerOpts) (func(), error) {
+	propagator, err := NewPropagator(opts.Propagators...)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := newOTLPExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tp, err := newTraceproviderWithExporter(c.serviceName, exporter, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.tracerProvider = tp
+	c.propagator = propagator
+
+	return shutdownFunc(ctx, tp), nil
+}
+
+// NewRootSpan starts a new root span with the client tracer.
+func (c *Client) NewRootSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return c.Tracer().Start(ctx, name, append(opts, trace.WithNewRoot())...)
+}
+
+// NewChildSpan starts a new child span with the client tracer.
+func (c *Client) NewChildSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return c.Tracer().Start(ctx, name, opts...)
+}
+
+// NewAutoNamedChildSpan starts a new child span with the client tracer, named after the calling function.
+func (c *Client) NewAutoNamedChildSpan(ctx context.Context, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return c.Tracer().Start(ctx, getCallingFuncName(), opts...)
+}
+
+// LoggerFromContext extracts the logrus logger from the context. If it's not found, or if the context logger
+// is not a logrus one, the client logger is returned.
+// The trace context, service name, flight id, user id and namespace of the context are added as fields,
+// and the context is attached to the entry for the hooks, such as the one of otellog.NewLogrusHook.
+func (c *Client) LoggerFromContext(ctx context.Context) *logrus.Entry {
+	val, ok := ctx.Value(logKey{}).(logrusAdapter)
+	if !ok {
+		le := logrus.NewEntry(c.logrusLogger())
+		le.Debug("log not found in context, using default")
+		return le.WithContext(ctx).WithFields(logrus.Fields(c.logFields(ctx)))
+	}
+
+	return val.entry.WithContext(ctx).WithFields(logrus.Fields(c.logFields(ctx)))
+}
+
+// StructuredLoggerFromContext extracts the logger from the context, whatever its backend. If it's not found,
+// the client structured logger is returned. The trace context, service name, flight id, user id and namespace
+// of the context are added as fields, and the logrus and slog backends get the context.
+func (c *Client) StructuredLoggerFromContext(ctx context.Context) Logger {
+	return loggerWithContext(ctx, c.storedLogger(ctx)).WithFields(c.logFields(ctx))
+}
+
+// storedLogger returns the logger of the context, or the client structured logger, without the context fields,
+// so that they are added once per returned logger and never stored in the context.
+func (c *Client) storedLogger(ctx context.Context) Logger {
+	if val, ok := ctx.Value(logKey{}).(Logger); ok {
+		return val
+	}
+	if c.structuredLogger != nil {
+		return c.structuredLogger
+	}
+	le := logrus.NewEntry(c.logrusLogger())
+	le.Debug("log not found in context, using default")
+	return NewLogrusAdapter(le)
+}
+
+func (c *Client) logrusLogger() *logrus.Logger {
+	if c.logger == nil {
+		return logrus.StandardLogger()
+	}
+	return c.logger
+}
+
+// logFields returns the trace context, service name and request identity of ctx, named after the client field names.
+func (c *Client) logFields(ctx context.Context) Fields {
+	names := DefaultLogFieldNames
+	if c.logFieldNames != nil {
+		names = *c.logFieldNames
+	}
+	return names.fields(ctx, c.serviceName)
+}
+
+// LogTraceInfo logs the given message to the logger obtained from the context and records the message in the trace span.
+func (c *Client) LogTraceInfo(ctx context.Context, msg string, fields ...logrus.Fields) {
+	logTraceInfo(ctx, c.StructuredLoggerFromContext(ctx), msg, fields...)
+}
+
+// LogTraceError logs the provided error and message to the logger obtained from the context,
+// records the error in the trace span and sets the status of the span to Error.
+func (c *Client) LogTraceError(ctx context.Context, err error, errMsg string, fields ...logrus.Fields) {
+	logTraceError(ctx, c.StructuredLoggerFromContext(ctx), err, errMsg, fields...)
+}
+
+func (c *Client) loggerAddField(ctx context.Context, key string, value interface{}) context.Context {
+	return context.WithValue(ctx, logKey{}, c.storedLogger(ctx).WithFields(Fields{key: value}))
+}
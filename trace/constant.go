@@ -11,6 +11,8 @@ const (
 
 	FlightID = "x-flight-id"
 
+	namespacePathParameter = "namespace"
+
 	HTTPMethodKey     = attribute.Key("http.method")
 	HTTPStatusCodeKey = attribute.Key("http.status_code")
 	HTTPFlavorKey     = attribute.Key("http.flavor")
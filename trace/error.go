@@ -21,15 +21,17 @@ import (
 //
 // This function does not return any values.
 func LogTraceError(ctx context.Context, err error, errMsg string, fields ...logrus.Fields) {
+	defaultClient.LogTraceError(ctx, err, errMsg, fields...)
+}
+
+func logTraceError(ctx context.Context, logger Logger, err error, errMsg string, fields ...logrus.Fields) {
 	span := SpanFromContext(ctx)
-	log := LoggerFromContext(ctx).WithFields(mergeFields(fields...))
+	log := logger.WithFields(mergeFields(fields...))
 	span.SetStatus(codes.Error, errMsg)
-	if err == nil {
-		log.Error(errMsg)
-		return
+	log.Error(err, errMsg)
+	if err != nil {
+		span.RecordError(err)
 	}
-	log.WithError(err).Error(errMsg)
-	span.RecordError(err)
 }
 
 // TraceError record the current error in trace span without log message.
@@ -45,8 +47,8 @@ func TraceError(ctx context.Context, err error, errMsg string) {
 }
 
 // Helper function to merge multiple logrus.Fields dictionaries into one
-func mergeFields(fieldsSlice ...logrus.Fields) logrus.Fields {
-	result := logrus.Fields{}
+func mergeFields(fieldsSlice ...logrus.Fields) Fields {
+	result := Fields{}
 	for _, fields := range fieldsSlice {
 		for k, v := range fields {
 			result[k] = v
@@ -0,0 +1,179 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ExporterProtocol is the transport used to send the spans to the OTLP collector.
+type ExporterProtocol string
+
+const (
+	ExporterProtocolGRPC ExporterProtocol = "grpc"
+	ExporterProtocolHTTP ExporterProtocol = "http"
+)
+
+// TracerOpts represents the OTLP exporter, batch span processor, sampler and propagator configuration options
+// of SetUpTracerWithOptions.
+type TracerOpts struct {
+	Endpoint string           // host:port of the collector
+	Protocol ExporterProtocol // default is ExporterProtocolGRPC
+	URLPath  string           // OTLP/HTTP only, default is /v1/traces
+
+	Insecure  bool        // disables TLS, default is false = TLS with the system certificates
+	TLSConfig *tls.Config // custom TLS configuration, e.g. for a private CA or mTLS
+	Headers   map[string]string
+	Gzip      bool // compresses the exported spans with gzip
+
+	// ConnectTimeout blocks the OTLP/gRPC setup until the collector is reachable, 0 = connect in the background.
+	// It is ignored by OTLP/HTTP which connects on each export.
+	ConnectTimeout time.Duration
+	ExportTimeout  time.Duration // timeout of each export request, default is 10 seconds
+	Retry          *RetryOpts    // default is the exporter retry with exponential backoff
+
+	Batch       BatchOpts
+	Sampler     sdktrace.Sampler // default is to sample every trace, following the parent decision, see NewSampler
+	Propagators []Propagator     // formats injected by the set up propagator, default is DefaultPropagators
+}
+
+// RetryOpts represents the retry with exponential backoff of the failed exports.
+type RetryOpts struct {
+	Enabled         bool
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxElapsedTime  time.Duration
+}
+
+// BatchOpts represents the batch span processor tuning, zero values keep the SDK defaults.
+type BatchOpts struct {
+	MaxQueueSize       int
+	MaxExportBatchSize int
+	BatchTimeout       time.Duration
+	ExportTimeout      time.Duration
+}
+
+// SetUpTracerWithOptions sets up the global tracer provider for serviceName with an OTLP exporter configured by opts.
+// It returns a function flushing and shutting down the tracer provider.
+func SetUpTracerWithOptions(ctx context.Context, opts TracerOpts) (func(), error) {
+	if _, err := NewPropagator(opts.Propagators...); err != nil {
+		return nil, err
+	}
+
+	exporter, err := newOTLPExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tp, err := setupTraceproviderWithExporter(defaultClient.serviceName, exporter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return shutdownFunc(ctx, tp), nil
+}
+
+func newOTLPExporter(ctx context.Context, opts TracerOpts) (*otlptrace.Exporter, error) {
+	var client otlptrace.Client
+	switch opts.Protocol {
+	case ExporterProtocolGRPC, "":
+		client = newGRPCClient(opts)
+	case ExporterProtocolHTTP:
+		client = newHTTPClient(opts)
+	default:
+		return nil, fmt.Errorf("unsupported exporter protocol %q", opts.Protocol)
+	}
+
+	if opts.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.ConnectTimeout)
+		defer cancel()
+	}
+
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+func newGRPCClient(opts TracerOpts) otlptrace.Client {
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	switch {
+	case opts.Insecure:
+		options = append(options, otlptracegrpc.WithInsecure())
+	case opts.TLSConfig != nil:
+		options = append(options, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(opts.TLSConfig)))
+	}
+	if len(opts.Headers) > 0 {
+		options = append(options, otlptracegrpc.WithHeaders(opts.Headers))
+	}
+	if opts.Gzip {
+		options = append(options, otlptracegrpc.WithCompressor("gzip"))
+	}
+	if opts.ConnectTimeout > 0 {
+		options = append(options, otlptracegrpc.WithDialOption(grpc.WithBlock()))
+	}
+	if opts.ExportTimeout > 0 {
+		options = append(options, otlptracegrpc.WithTimeout(opts.ExportTimeout))
+	}
+	if opts.Retry != nil {
+		options = append(options, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig(*opts.Retry)))
+	}
+	return otlptracegrpc.NewClient(options...)
+}
+
+func newHTTPClient(opts TracerOpts) otlptrace.Client {
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+	if opts.URLPath != "" {
+		options = append(options, otlptracehttp.WithURLPath(opts.URLPath))
+	}
+	switch {
+	case opts.Insecure:
+		options = append(options, otlptracehttp.WithInsecure())
+	case opts.TLSConfig != nil:
+		options = append(options, otlptracehttp.WithTLSClientConfig(opts.TLSConfig))
+	}
+	if len(opts.Headers) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(opts.Headers))
+	}
+	if opts.Gzip {
+		options = append(options, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+	if opts.ExportTimeout > 0 {
+		options = append(options, otlptracehttp.WithTimeout(opts.ExportTimeout))
+	}
+	if opts.Retry != nil {
+		options = append(options, otlptracehttp.WithRetry(otlptracehttp.RetryConfig(*opts.Retry)))
+	}
+	return otlptracehttp.NewClient(options...)
+}
+
+func (b BatchOpts) spanProcessorOptions() []sdktrace.BatchSpanProcessorOption {
+	var options []sdktrace.BatchSpanProcessorOption
+	if b.MaxQueueSize > 0 {
+		options = append(options, sdktrace.WithMaxQueueSize(b.MaxQueueSize))
+	}
+	if b.MaxExportBatchSize > 0 {
+		options = append(options, sdktrace.WithMaxExportBatchSize(b.MaxExportBatchSize))
+	}
+	if b.BatchTimeout > 0 {
+		options = append(options, sdktrace.WithBatchTimeout(b.BatchTimeout))
+	}
+	if b.ExportTimeout > 0 {
+		options = append(options, sdktrace.WithExportTimeout(b.ExportTimeout))
+	}
+	return options
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// otlpReceiver is an in-process OTLP trace receiver recording the exported span names and request headers.
+type otlpReceiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	mu      sync.Mutex
+	spans   []string
+	headers map[string]string
+}
+
+func (r *otlpReceiver) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	headers := map[string]string{}
+	for key, values := range md {
+		headers[key] = values[0]
+	}
+	r.record(req, headers)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (r *otlpReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body := io.Reader(req.Body)
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = gz
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var exportRequest coltracepb.ExportTraceServiceRequest
+	if err = proto.Unmarshal(data, &exportRequest); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	headers := map[string]string{}
+	for key := range req.Header {
+		headers[key] = req.Header.Get(key)
+	}
+	r.record(&exportRequest, headers)
+
+	response, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(response)
+}
+
+func (r *otlpReceiver) record(req *coltracepb.ExportTraceServiceRequest, headers map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.headers = headers
+	for _, resourceSpans := range req.GetResourceSpans() {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			for _, span := range scopeSpans.GetSpans() {
+				r.spans = append(r.spans, span.GetName())
+			}
+		}
+	}
+}
+
+func (r *otlpReceiver) received() ([]string, map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.spans, r.headers
+}
+
+func TestSetUpTracerWithOptionsGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver := &otlpReceiver{}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, receiver)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	setDefaultPropagator()
+	global := otel.GetTextMapPropagator()
+	client := NewClient("test", "test", nil)
+	shutdown, err := client.SetUpTracerWithOptions(context.Background(), TracerOpts{
+		Propagators:    []Propagator{PropagatorTraceContext},
+		Endpoint:       listener.Addr().String(),
+		Insecure:       true,
+		Headers:        map[string]string{"authorization": "Bearer token"},
+		Gzip:           true,
+		ConnectTimeout: 5 * time.Second,
+		ExportTimeout:  5 * time.Second,
+		Retry:          &RetryOpts{Enabled: false},
+		Batch:          BatchOpts{MaxExportBatchSize: 10, BatchTimeout: time.Millisecond},
+	})
+	require.NoError(t, err)
+
+	_, span := client.NewRootSpan(context.Background(), "grpc-span")
+	span.End()
+	shutdown()
+
+	spans, headers := receiver.received()
+	assert.Equal(t, []string{"grpc-span"}, spans)
+	assert.Equal(t, "Bearer token", headers["authorization"])
+	assert.Equal(t, global, otel.GetTextMapPropagator())
+	assert.Contains(t, client.Propagator().Fields(), "traceparent")
+}
+
+func TestSetUpTracerWithOptionsHTTP(t *testing.T) {
+	receiver := &otlpReceiver{}
+	server := httptest.NewTLSServer(receiver)
+	defer server.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	client := NewClient("test", "test", nil)
+	shutdown, err := client.SetUpTracerWithOptions(context.Background(), TracerOpts{
+		Endpoint:  server.Listener.Addr().String(),
+		Protocol:  ExporterProtocolHTTP,
+		TLSConfig: &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12},
+		Headers:   map[string]string{"X-Api-Key": "secret"},
+		Gzip:      true,
+	})
+	require.NoError(t, err)
+
+	_, span := client.NewRootSpan(context.Background(), "http-span")
+	span.End()
+	shutdown()
+
+	spans, headers := receiver.received()
+	assert.Equal(t, []string{"http-span"}, spans)
+	assert.Equal(t, "secret", headers["X-Api-Key"])
+	assert.Equal(t, "gzip", headers["Content-Encoding"])
+}
+
+func TestSetUpTracerWithOptionsUnsupportedProtocol(t *testing.T) {
+	_, err := NewClient("test", "test", nil).SetUpTracerWithOptions(context.Background(), TracerOpts{Protocol: "thrift"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/AccelByte/observability-go-sdk/internal/grpcwrap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCOpts represents the gRPC interceptors configuration options.
+type GRPCOpts struct {
+	// ExcludeMethods consists of blacklisted full method names to not send tracer,
+	// eg. key: /grpc.health.v1.Health/Check , value : boolean
+	ExcludeMethods map[string]bool
+
+	// UserIDExtractor returns the user id of the server request, i.e. from the authenticated token.
+	UserIDExtractor func(ctx context.Context) string
+
+	// NamespaceExtractor returns the namespace of the server request, i.e. from the authenticated token.
+	NamespaceExtractor func(ctx context.Context) string
+}
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor that will start a span
+// with attributes for user id and flight id. It is the gRPC equivalent of InstrumentCommonAttributes.
+func UnaryServerInterceptor(tracerName string, opts GRPCOpts) grpc.UnaryServerInterceptor {
+	return NewClient(tracerName, defaultClient.serviceName, nil).UnaryServerInterceptor(opts)
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that will start a span
+// with attributes for user id and flight id.
+func StreamServerInterceptor(tracerName string, opts GRPCOpts) grpc.StreamServerInterceptor {
+	return NewClient(tracerName, defaultClient.serviceName, nil).StreamServerInterceptor(opts)
+}
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that will start a client span
+// and propagate it with the configured propagator.
+func UnaryClientInterceptor(tracerName string, opts GRPCOpts) grpc.UnaryClientInterceptor {
+	return NewClient(tracerName, defaultClient.serviceName, nil).UnaryClientInterceptor(opts)
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that will start a client span
+// and propagate it with the configured propagator.
+func StreamClientInterceptor(tracerName string, opts GRPCOpts) grpc.StreamClientInterceptor {
+	return NewClient(tracerName, defaultClient.serviceName, nil).StreamClientInterceptor(opts)
+}
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor that will start a span
+// with attributes for user id and flight id using the client tracer.
+func (c *Client) UnaryServerInterceptor(opts GRPCOpts) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if opts.ExcludeMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, span := c.startGRPCServerSpan(ctx, info.FullMethod, opts)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		setGRPCServerStatus(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC stream server interceptor that will start a span
+// with attributes for user id and flight id using the client tracer.
+func (c *Client) StreamServerInterceptor(opts GRPCOpts) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if opts.ExcludeMethods[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, span := c.startGRPCServerSpan(ss.Context(), info.FullMethod, opts)
+		defer span.End()
+
+		err := handler(srv, &serverStreamWithContext{ServerStream: ss, ctx: ctx})
+		setGRPCServerStatus(span, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor returns a gRPC unary client interceptor that will start a client span
+// using the client tracer and propagate it with the configured propagator.
+func (c *Client) UnaryClientInterceptor(opts GRPCOpts) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		if opts.ExcludeMethods[method] {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		ctx, span := c.startGRPCClientSpan(ctx, method)
+		defer span.End()
+
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		setGRPCClientStatus(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a gRPC stream client interceptor that will start a client span
+// using the client tracer and propagate it with the configured propagator.
+// The span ends when RecvMsg returns an error, io.EOF included, or when it returns the response of a client-streaming RPC.
+func (c *Client) StreamClientInterceptor(opts GRPCOpts) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if opts.ExcludeMethods[method] {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+
+		ctx, span := c.startGRPCClientSpan(ctx, method)
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			setGRPCClientStatus(span, err)
+			span.End()
+			return nil, err
+		}
+		return &tracedClientStream{ClientStream: stream, serverStreams: desc.ServerStreams, span: span}, nil
+	}
+}
+
+func (c *Client) startGRPCServerSpan(ctx context.Context, fullMethod string, opts GRPCOpts) (context.Context, oteltrace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = c.Propagator().Extract(ctx, metadataCarrier(md))
+
+	flightID := firstMetadataValue(md, FlightID)
+	ctx = ContextWithFlightID(ctx, flightID)
+
+	var userID string
+	if opts.UserIDExtractor != nil {
+		userID = opts.UserIDExtractor(ctx)
+	}
+
+	ctx = ContextWithUserID(ctx, userID)
+
+	var namespace string
+	if opts.NamespaceExtractor != nil {
+		namespace = opts.NamespaceExtractor(ctx)
+	}
+
+	ctx = ContextWithNamespace(ctx, namespace)
+
+	spanOpts := []oteltrace.SpanStartOption{
+		oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+		oteltrace.WithAttributes(grpcAttributes(fullMethod)...),
+		oteltrace.WithAttributes(attribute.String("user.id", userID)),
+		oteltrace.WithAttributes(attribute.String("flight.id", flightID)),
+	}
+
+	return c.Tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"), spanOpts...)
+}
+
+func (c *Client) startGRPCClientSpan(ctx context.Context, fullMethod string) (context.Context, oteltrace.Span) {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	// forward the flight id of the incoming request to the downstream service
+	if len(md.Get(FlightID)) == 0 {
+		if flightID := FlightIDFromContext(ctx); flightID != "" {
+			md.Set(FlightID, flightID)
+		}
+	}
+
+	ctx, span := c.Tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(grpcAttributes(fullMethod)...),
+		oteltrace.WithAttributes(attribute.String("flight.id", firstMetadataValue(md, FlightID))),
+	)
+
+	c.Propagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+func grpcAttributes(fullMethod string) []attribute.KeyValue {
+	service, method := grpcwrap.SplitFullMethod(fullMethod)
+	return []attribute.KeyValue{semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)}
+}
+
+// setGRPCServerStatus marks the span as error only for the codes caused by the server,
+// like HTTPServerStatus does for the 5xx status codes.
+func setGRPCServerStatus(span oteltrace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	switch s.Code() {
+	case grpccodes.Unknown, grpccodes.DeadlineExceeded, grpccodes.Unimplemented,
+		grpccodes.Internal, grpccodes.Unavailable, grpccodes.DataLoss:
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+func setGRPCClientStatus(span oteltrace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// metadataCarrier adapts metadata.MD to propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (m metadataCarrier) Get(key string) string {
+	return firstMetadataValue(metadata.MD(m), key)
+}
+
+func (m metadataCarrier) Set(key, value string) {
+	metadata.MD(m).Set(key, value)
+}
+
+func (m metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// serverStreamWithContext overrides the stream context to pass the span to the handler.
+type serverStreamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStreamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+// tracedClientStream ends the span once the stream has ended.
+type tracedClientStream struct {
+	grpc.ClientStream
+	// serverStreams is false for the client-streaming RPCs, ending with their single response
+	serverStreams bool
+	once          sync.Once
+	span          oteltrace.Span
+}
+
+func (s *tracedClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil && !s.serverStreams {
+		s.once.Do(func() {
+			setGRPCClientStatus(s.span, nil)
+			s.span.End()
+		})
+	}
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				setGRPCClientStatus(s.span, nil)
+			} else {
+				setGRPCClientStatus(s.span, err)
+			}
+			s.span.End()
+		})
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCInterceptors(t *testing.T) {
+	setDefaultPropagator()
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+
+	// the client interceptor injects the span and flight id in the outgoing metadata
+	var outgoing metadata.MD
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(FlightID, "flight-1"))
+	err := client.UnaryClientInterceptor(GRPCOpts{})(ctx, "/accelbyte.bans.v1.Bans/GetBan", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			outgoing, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+	require.NoError(t, err)
+
+	// the server interceptor extracts them from the incoming metadata
+	serverInterceptor := client.UnaryServerInterceptor(GRPCOpts{
+		UserIDExtractor:    func(ctx context.Context) string { return "user-1" },
+		NamespaceExtractor: func(ctx context.Context) string { return "accelbyte" },
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/accelbyte.bans.v1.Bans/GetBan"}
+	var namespace string
+	_, err = serverInterceptor(metadata.NewIncomingContext(context.Background(), outgoing), nil, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			namespace = NamespaceFromContext(ctx)
+			return nil, status.Error(grpccodes.Internal, "request to DB failed")
+		})
+	require.Error(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	clientSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "accelbyte.bans.v1.Bans/GetBan", serverSpan.Name)
+	assert.Equal(t, clientSpan.SpanContext.SpanID(), serverSpan.Parent.SpanID())
+	assert.Equal(t, codes.Error, serverSpan.Status.Code)
+	assert.Contains(t, serverSpan.Attributes, attribute.String("user.id", "user-1"))
+	assert.Contains(t, serverSpan.Attributes, attribute.String("flight.id", "flight-1"))
+	assert.Contains(t, serverSpan.Attributes, attribute.String("rpc.service", "accelbyte.bans.v1.Bans"))
+	assert.Equal(t, "accelbyte", namespace)
+}
+
+// responseClientStream is a client stream receiving a single response, like a client-streaming RPC.
+type responseClientStream struct {
+	grpc.ClientStream
+}
+
+func (responseClientStream) RecvMsg(interface{}) error {
+	return nil
+}
+
+func TestStreamClientInterceptorClientStreaming(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+
+	desc := &grpc.StreamDesc{ClientStreams: true}
+	stream, err := client.StreamClientInterceptor(GRPCOpts{})(context.Background(), desc, nil, "/accelbyte.bans.v1.Bans/ImportBans",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return responseClientStream{}, nil
+		})
+	require.NoError(t, err)
+	require.NoError(t, stream.RecvMsg(nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "accelbyte.bans.v1.Bans/ImportBans", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"net/http"
+
+	"github.com/AccelByte/observability-go-sdk/internal/httpwrap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// RouteExtractor returns the route template of the request, i.e. /bans/{banId}, or an empty string
+// when the route is not known.
+type RouteExtractor func(r *http.Request) string
+
+// ServeMuxRouteExtractor returns a RouteExtractor that reads the matching pattern from mux.
+func ServeMuxRouteExtractor(mux *http.ServeMux) RouteExtractor {
+	return func(r *http.Request) string {
+		return httpwrap.ServeMuxRoute(mux, r)
+	}
+}
+
+// HTTPMiddlewareOpts represents the net/http middleware configuration options.
+type HTTPMiddlewareOpts struct {
+	// ExcludeEndpoints consists of blacklisted endpoint name to not send tracer,
+	// eg. key: /healthz , key2: map of http.Method (GET, POST, DELETE, PUT) ,  value : boolean
+	ExcludeEndpoints map[string]map[string]bool
+
+	// RouteExtractor is used to name the span. It is called before and, if the route is still unknown,
+	// after serving the request, so routers that resolve the route while serving are supported.
+	// Excluded endpoints can only be matched when the route is known before serving.
+	RouteExtractor RouteExtractor
+
+	// UserIDExtractor returns the user id of the request, i.e. from the authenticated token.
+	UserIDExtractor func(r *http.Request) string
+
+	// NamespaceExtractor returns the namespace of the request, i.e. from the path or the authenticated token.
+	NamespaceExtractor func(r *http.Request) string
+}
+
+// HTTPMiddleware returns a net/http middleware that will start a span with attributes for user id and flight id.
+// It is the net/http equivalent of InstrumentCommonAttributes.
+func HTTPMiddleware(tracerName string, opts HTTPMiddlewareOpts) func(http.Handler) http.Handler {
+	return NewClient(tracerName, defaultClient.serviceName, nil).HTTPMiddleware(opts)
+}
+
+// HTTPMiddleware returns a net/http middleware that will start a span with attributes for user id and flight id
+// using the client tracer.
+func (c *Client) HTTPMiddleware(opts HTTPMiddlewareOpts) func(http.Handler) http.Handler {
+	extractRoute := func(r *http.Request) string {
+		if opts.RouteExtractor == nil {
+			return ""
+		}
+		return opts.RouteExtractor(r)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := c.Propagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			route := extractRoute(r)
+
+			// end process if route = blacklisted endpoints
+			if opts.ExcludeEndpoints[route][r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			flightID := r.Header.Get(FlightID)
+			ctx = ContextWithFlightID(ctx, flightID)
+
+			var userID string
+			if opts.UserIDExtractor != nil {
+				userID = opts.UserIDExtractor(r)
+			}
+
+			ctx = ContextWithUserID(ctx, userID)
+
+			var namespace string
+			if opts.NamespaceExtractor != nil {
+				namespace = opts.NamespaceExtractor(r)
+			}
+
+			ctx = ContextWithNamespace(ctx, namespace)
+
+			spanOpts := []oteltrace.SpanStartOption{
+				oteltrace.WithAttributes(HTTPServerRequest(r)...),
+				oteltrace.WithAttributes(attribute.String("user.id", userID)),
+				oteltrace.WithAttributes(attribute.String("flight.id", flightID)),
+			}
+
+			spanName := route
+			if route != "" {
+				spanOpts = append(spanOpts, oteltrace.WithAttributes(semconv.HTTPRoute(route)))
+			} else {
+				spanName = "HTTP " + r.Method
+			}
+
+			ctx, span := c.Tracer().Start(ctx, spanName, spanOpts...)
+			defer span.End()
+
+			recorder, rw := httpwrap.NewStatusRecorder(w)
+			r = r.WithContext(ctx)
+			next.ServeHTTP(rw, r)
+
+			if route == "" {
+				if route = extractRoute(r); route != "" {
+					span.SetName(route)
+					span.SetAttributes(semconv.HTTPRoute(route))
+				}
+			}
+
+			status := recorder.StatusCode
+			span.SetStatus(HTTPServerStatus(status))
+			if status > 0 {
+				span.SetAttributes(semconv.HTTPStatusCode(status))
+			}
+		})
+	}
+}
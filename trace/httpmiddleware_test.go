@@ -0,0 +1,57 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+
+	mux := http.NewServeMux()
+	var namespace string
+	mux.HandleFunc("/bans/", func(w http.ResponseWriter, r *http.Request) {
+		namespace = NamespaceFromContext(r.Context())
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	handler := client.HTTPMiddleware(HTTPMiddlewareOpts{
+		ExcludeEndpoints: map[string]map[string]bool{"/healthz": {http.MethodGet: true}},
+		RouteExtractor:   ServeMuxRouteExtractor(mux),
+		UserIDExtractor: func(r *http.Request) string {
+			return "user-1"
+		},
+		NamespaceExtractor: func(r *http.Request) string {
+			return "accelbyte"
+		},
+	})(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/bans/1", nil)
+	req.Header.Set(FlightID, "flight-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/bans/", spans[0].Name)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Contains(t, spans[0].Attributes, attribute.String("user.id", "user-1"))
+	assert.Contains(t, spans[0].Attributes, attribute.String("flight.id", "flight-1"))
+	assert.Contains(t, spans[0].Attributes, attribute.Int("http.status_code", http.StatusBadGateway))
+	assert.Equal(t, "accelbyte", namespace)
+}
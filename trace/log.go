@@ -17,22 +17,12 @@ type logKey struct{}
 // LoggerFromContext extracts the logger from the context. If it's not found, a logger with default settings is returned
 // For web API endpoints, the Logger go-restful filter is usually used to add the logger in the request context.
 func LoggerFromContext(ctx context.Context) *logrus.Entry {
-	val, ok := ctx.Value(logKey{}).(*logrus.Entry)
-	if !ok {
-		le := logrus.NewEntry(logrus.StandardLogger())
-		le.Debug("log not found in context, using default")
-		return le.WithField(LogFieldTraceID, TraceIDFromContext(ctx))
-	}
-
-	return val.WithField(LogFieldTraceID, TraceIDFromContext(ctx))
+	return defaultClient.LoggerFromContext(ctx)
 }
 
 // LoggerAddField extracts the logger in the context and adds a field with the given key and value
 func LoggerAddField(ctx context.Context, key string, value interface{}) context.Context {
-	log := LoggerFromContext(ctx)
-	le := log.WithField(key, value)
-	le.Level = log.Level
-	return context.WithValue(ctx, logKey{}, le)
+	return defaultClient.loggerAddField(ctx, key, value)
 }
 
 // ContextWithLogger inserts a log entry from l into ctx and returns the updated context. Note that this
@@ -41,15 +31,35 @@ func LoggerAddField(ctx context.Context, key string, value interface{}) context.
 func ContextWithLogger(ctx context.Context, l *logrus.Logger) context.Context {
 	le := logrus.NewEntry(l)
 	le.Level = l.Level
-	return context.WithValue(ctx, logKey{}, le)
+	return context.WithValue(ctx, logKey{}, NewLogrusAdapter(le))
+}
+
+// ContextWithStructuredLogger inserts l into ctx and returns the updated context, like ContextWithLogger
+// for any logging backend. The logger is used by LogTraceInfo and LogTraceError.
+func ContextWithStructuredLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, logKey{}, l)
+}
+
+// StructuredLoggerFromContext extracts the logger from the context, whatever its backend.
+// If it's not found, a logrus logger with default settings is returned.
+func StructuredLoggerFromContext(ctx context.Context) Logger {
+	return defaultClient.StructuredLoggerFromContext(ctx)
 }
 
 // LogTraceInfo logs the given message to the logger obtained from the context and records the message in the trace span.
 //
 // This function does not return any values.
 func LogTraceInfo(ctx context.Context, msg string, fields ...logrus.Fields) {
-	log := LoggerFromContext(ctx).WithFields(mergeFields(fields...))
-	log.WithField("time", time.Now().Format(time.RFC3339)).Info(msg)
+	defaultClient.LogTraceInfo(ctx, msg, fields...)
+}
+
+func logTraceInfo(ctx context.Context, logger Logger, msg string, fields ...logrus.Fields) {
+	log := logger.WithFields(mergeFields(fields...))
+	// slog and zap already log the time under the same key
+	if _, ok := log.(logrusAdapter); ok {
+		log = log.WithFields(Fields{"time": time.Now().Format(time.RFC3339)})
+	}
+	log.Info(msg)
 	SpanFromContext(ctx).AddEvent(msg)
 }
 
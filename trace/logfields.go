@@ -0,0 +1,112 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"encoding/binary"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogFieldNames are the names of the fields added to the context loggers. An empty name omits the field.
+type LogFieldNames struct {
+	TraceID     string
+	SpanID      string
+	TraceFlags  string // hex encoded W3C trace flags, e.g. "01"
+	Sampled     string // boolean sampling decision
+	ServiceName string
+	FlightID    string
+	UserID      string
+	Namespace   string
+	// DecimalIDs formats the trace id and span id as the decimal value of their lower 64 bits, as Datadog expects.
+	DecimalIDs bool
+}
+
+var (
+	// OTelLogFieldNames follows the OpenTelemetry log data model.
+	OTelLogFieldNames = LogFieldNames{
+		TraceID:     LogFieldTraceID,
+		SpanID:      LogFieldSpanID,
+		TraceFlags:  LogFieldTraceFlags,
+		ServiceName: LogFieldServiceName,
+		FlightID:    LogFieldFlightID,
+		UserID:      LogFieldUserID,
+		Namespace:   LogFieldNamespace,
+	}
+
+	// DatadogLogFieldNames follows the Datadog logs and traces correlation attributes.
+	DatadogLogFieldNames = LogFieldNames{
+		TraceID:     "dd.trace_id",
+		SpanID:      "dd.span_id",
+		ServiceName: "dd.service",
+		FlightID:    LogFieldFlightID,
+		UserID:      "usr.id",
+		Namespace:   LogFieldNamespace,
+		DecimalIDs:  true,
+	}
+
+	// LokiLogFieldNames follows the Grafana Loki derived fields convention linking logs to Tempo traces.
+	LokiLogFieldNames = LogFieldNames{
+		TraceID:     "traceID",
+		SpanID:      "spanID",
+		Sampled:     "sampled",
+		ServiceName: "service_name",
+		FlightID:    LogFieldFlightID,
+		UserID:      LogFieldUserID,
+		Namespace:   LogFieldNamespace,
+	}
+
+	// ECSLogFieldNames follows the Elastic Common Schema.
+	ECSLogFieldNames = LogFieldNames{
+		TraceID:     "trace.id",
+		SpanID:      "span.id",
+		ServiceName: "service.name",
+		FlightID:    "labels.flight_id",
+		UserID:      "user.id",
+		Namespace:   "labels.namespace",
+	}
+
+	// DefaultLogFieldNames are the field names used by the clients created without Opts.LogFieldNames.
+	DefaultLogFieldNames = OTelLogFieldNames
+)
+
+// fields returns the trace context, service name and request identity of ctx named after n.
+// The trace fields are omitted when ctx has no valid span context.
+func (n LogFieldNames) fields(ctx context.Context, serviceName string) Fields {
+	fields := Fields{}
+	set := func(name string, value interface{}) {
+		if name != "" {
+			fields[name] = value
+		}
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		if n.DecimalIDs {
+			traceID, spanID := sc.TraceID(), sc.SpanID()
+			set(n.TraceID, strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10))
+			set(n.SpanID, strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10))
+		} else {
+			set(n.TraceID, sc.TraceID().String())
+			set(n.SpanID, sc.SpanID().String())
+		}
+		set(n.TraceFlags, sc.TraceFlags().String())
+		set(n.Sampled, sc.IsSampled())
+	}
+	if serviceName != "" {
+		set(n.ServiceName, serviceName)
+	}
+	if flightID := FlightIDFromContext(ctx); flightID != "" {
+		set(n.FlightID, flightID)
+	}
+	if userID := UserIDFromContext(ctx); userID != "" {
+		set(n.UserID, userID)
+	}
+	if namespace := NamespaceFromContext(ctx); namespace != "" {
+		set(n.Namespace, namespace)
+	}
+	return fields
+}
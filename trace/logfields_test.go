@@ -0,0 +1,64 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestLogFieldsWithoutSpan(t *testing.T) {
+	client := NewClient("test", "bans", &Opts{Logger: logrus.New()})
+
+	entry := client.LoggerFromContext(ContextWithFlightID(context.Background(), "flight-1"))
+
+	assert.Equal(t, logrus.Fields{LogFieldServiceName: "bans", LogFieldFlightID: "flight-1"}, entry.Data)
+	assert.Empty(t, TraceIDFromContext(context.Background()))
+}
+
+func TestLogFieldNames(t *testing.T) {
+	ctx := oteltrace.ContextWithRemoteSpanContext(context.Background(), propagatedSpanContext)
+	ctx = ContextWithUserID(ctx, "user-1")
+
+	for name, tc := range map[string]struct {
+		names    LogFieldNames
+		expected logrus.Fields
+	}{
+		"otel": {OTelLogFieldNames, logrus.Fields{
+			"trace_id":     "4bf92f3577b34da6a3ce929d0e0e4736",
+			"span_id":      "00f067aa0ba902b7",
+			"trace_flags":  "01",
+			"service.name": "bans",
+			"user_id":      "user-1",
+		}},
+		"datadog": {DatadogLogFieldNames, logrus.Fields{
+			"dd.trace_id": "11803532876627986230",
+			"dd.span_id":  "67667974448284343",
+			"dd.service":  "bans",
+			"usr.id":      "user-1",
+		}},
+		"loki": {LokiLogFieldNames, logrus.Fields{
+			"traceID":      "4bf92f3577b34da6a3ce929d0e0e4736",
+			"spanID":       "00f067aa0ba902b7",
+			"sampled":      true,
+			"service_name": "bans",
+			"user_id":      "user-1",
+		}},
+		"ecs": {ECSLogFieldNames, logrus.Fields{
+			"trace.id":     "4bf92f3577b34da6a3ce929d0e0e4736",
+			"span.id":      "00f067aa0ba902b7",
+			"service.name": "bans",
+			"user.id":      "user-1",
+		}},
+	} {
+		names := tc.names
+		client := NewClient("test", "bans", &Opts{Logger: logrus.New(), LogFieldNames: &names})
+		assert.Equal(t, tc.expected, client.LoggerFromContext(ctx).Data, name)
+	}
+}
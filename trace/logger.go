@@ -0,0 +1,129 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slogErrorKey is the attribute of the error logged by the slog adapter.
+const slogErrorKey = "error"
+
+// Fields is a set of structured log fields.
+type Fields map[string]interface{}
+
+// Logger is the structured logging backend of the trace logging helpers, such as LogTraceInfo and LogTraceError.
+// Use NewLogrusAdapter, NewSlogAdapter or tracezap.NewAdapter to plug an existing logger.
+type Logger interface {
+	// WithFields returns a logger adding fields to every log.
+	WithFields(fields Fields) Logger
+	Info(msg string)
+	// Error logs msg at error level, with err as a field if it is not nil.
+	Error(err error, msg string)
+}
+
+// NewLogrusAdapter returns a Logger writing to the logrus entry.
+func NewLogrusAdapter(entry *logrus.Entry) Logger {
+	return logrusAdapter{entry}
+}
+
+// logrusAdapter represents an internal Logger implementation for logrus
+type logrusAdapter struct {
+	entry *logrus.Entry
+}
+
+func (l logrusAdapter) WithFields(fields Fields) Logger {
+	le := l.entry.WithFields(logrus.Fields(fields))
+	le.Level = l.entry.Level
+	return logrusAdapter{le}
+}
+
+func (l logrusAdapter) withContext(ctx context.Context) Logger {
+	le := l.entry.WithContext(ctx)
+	le.Level = l.entry.Level
+	return logrusAdapter{le}
+}
+
+func (l logrusAdapter) Info(msg string) {
+	l.entry.Info(msg)
+}
+
+func (l logrusAdapter) Error(err error, msg string) {
+	if err == nil {
+		l.entry.Error(msg)
+		return
+	}
+	l.entry.WithError(err).Error(msg)
+}
+
+// NewSlogAdapter returns a Logger writing to the slog logger.
+func NewSlogAdapter(logger *slog.Logger) Logger {
+	return slogAdapter{logger: logger}
+}
+
+// slogAdapter represents an internal Logger implementation for log/slog
+type slogAdapter struct {
+	logger *slog.Logger
+	ctx    context.Context
+}
+
+func (l slogAdapter) WithFields(fields Fields) Logger {
+	args := make([]any, 0, 2*len(fields))
+	for _, key := range sortedKeys(fields) {
+		args = append(args, key, fields[key])
+	}
+	return slogAdapter{logger: l.logger.With(args...), ctx: l.ctx}
+}
+
+func (l slogAdapter) withContext(ctx context.Context) Logger {
+	return slogAdapter{logger: l.logger, ctx: ctx}
+}
+
+func (l slogAdapter) Info(msg string) {
+	l.logger.InfoContext(l.context(), msg)
+}
+
+func (l slogAdapter) Error(err error, msg string) {
+	if err == nil {
+		l.logger.ErrorContext(l.context(), msg)
+		return
+	}
+	l.logger.ErrorContext(l.context(), msg, slogErrorKey, err)
+}
+
+func (l slogAdapter) context() context.Context {
+	if l.ctx == nil {
+		return context.Background()
+	}
+	return l.ctx
+}
+
+// contextLogger is implemented by the Logger backends able to pass the context to their handlers and hooks,
+// e.g. for the OpenTelemetry logs bridge to read the span context.
+type contextLogger interface {
+	withContext(ctx context.Context) Logger
+}
+
+// loggerWithContext returns l passing ctx to its backend if it supports it, l otherwise.
+func loggerWithContext(ctx context.Context, l Logger) Logger {
+	if cl, ok := l.(contextLogger); ok {
+		return cl.withContext(ctx)
+	}
+	return l
+}
+
+// sortedKeys returns the field names in order, so that the fields are always logged in the same order.
+func sortedKeys(fields Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
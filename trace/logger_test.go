@@ -0,0 +1,100 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLogrusAdapterKeepsContextLogger(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	client := NewClient("test", "test", nil)
+
+	ctx := ContextWithLogger(context.Background(), logger)
+	ctx = client.loggerAddField(ctx, "ban_id", "1")
+	client.LoggerFromContext(ctx).Info("ban added")
+	client.LogTraceError(ctx, errors.New("failed"), "ban failed")
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "1", entries[0].Data["ban_id"])
+	assert.Equal(t, "ban failed", entries[1].Message)
+	assert.EqualError(t, entries[1].Data[logrus.ErrorKey].(error), "failed")
+}
+
+func TestSlogAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewClient("test", "test", &Opts{
+		TracerProvider:   sdktrace.NewTracerProvider(),
+		StructuredLogger: NewSlogAdapter(slog.New(slog.NewJSONHandler(&buf, nil))),
+	})
+
+	ctx, span := client.NewRootSpan(ContextWithFlightID(context.Background(), "flight-1"), "root")
+	defer span.End()
+	client.LogTraceInfo(ctx, "ban added", logrus.Fields{"ban_id": "1"})
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ban added", record["msg"])
+	assert.Equal(t, "1", record["ban_id"])
+	assert.Equal(t, "flight-1", record[LogFieldFlightID])
+	assert.Equal(t, span.SpanContext().TraceID().String(), record[LogFieldTraceID])
+}
+
+func TestSlogAdapterAddsContextFieldsOnce(t *testing.T) {
+	var buf bytes.Buffer
+	client := NewClient("test", "test", &Opts{
+		TracerProvider:   sdktrace.NewTracerProvider(),
+		StructuredLogger: NewSlogAdapter(slog.New(slog.NewJSONHandler(&buf, nil))),
+	})
+
+	ctx, span := client.NewRootSpan(context.Background(), "root")
+	defer span.End()
+	ctx = client.loggerAddField(ctx, "ban_id", "1")
+	ctx = client.loggerAddField(ctx, "user_id", "2")
+	client.LogTraceInfo(ctx, "ban added")
+
+	for _, key := range []string{"time", LogFieldTraceID, LogFieldSpanID, LogFieldServiceName, "ban_id"} {
+		assert.Equal(t, 1, strings.Count(buf.String(), `"`+key+`"`), key)
+	}
+}
+
+func TestSlogAdapterError(t *testing.T) {
+	var buf bytes.Buffer
+	NewSlogAdapter(slog.New(slog.NewJSONHandler(&buf, nil))).Error(errors.New("failed"), "ban failed")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ban failed", record["msg"])
+	assert.Equal(t, "failed", record["error"])
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil))).With("service", "bans")
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(ContextWithUserID(context.Background(), "user-1"), "root")
+	defer span.End()
+	logger.InfoContext(ctx, "ban added")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "bans", record["service"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), record[LogFieldTraceID])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record[LogFieldSpanID])
+	assert.Equal(t, "user-1", record[LogFieldUserID])
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// defaultScope is the instrumentation scope of the log records when Opts.Scope is not set.
+const defaultScope = "github.com/AccelByte/observability-go-sdk/trace/otellog"
+
+// Opts represents the log bridge configuration options.
+type Opts struct {
+	// LoggerProvider is where the log records are emitted, default is the global logger provider,
+	// looked up on each record so that the bridges follow the logger provider set up after their creation.
+	LoggerProvider log.LoggerProvider
+	// Scope is the instrumentation scope of the log records, e.g. the tracer name, default is this package path.
+	Scope string
+}
+
+// NewLogrusHook returns a logrus hook emitting every entry to the logger provider of opts, which may be nil.
+// The records carry the level as severity, the entry fields as attributes, and the span context
+// of the entry context, e.g. the one of trace.LoggerFromContext:
+//
+//	logrus.AddHook(otellog.NewLogrusHook(nil))
+func NewLogrusHook(opts *Opts) logrus.Hook {
+	return logrusHook{bridge: newBridge(opts)}
+}
+
+// NewSlogHandler returns a slog handler emitting every record to the logger provider of opts, which may be nil.
+// The records carry the level as severity, the attributes, with the group names as dot separated prefixes,
+// and the span context of the record context. Use it with the context aware slog functions:
+//
+//	logger := slog.New(otellog.NewSlogHandler(nil))
+//	logger.InfoContext(ctx, "ban added")
+func NewSlogHandler(opts *Opts) slog.Handler {
+	return slogHandler{bridge: newBridge(opts)}
+}
+
+// bridge holds the logger provider and scope of a log bridge.
+type bridge struct {
+	loggerProvider log.LoggerProvider
+	scope          string
+}
+
+func newBridge(opts *Opts) bridge {
+	b := bridge{scope: defaultScope}
+	if opts != nil {
+		b.loggerProvider = opts.LoggerProvider
+		if opts.Scope != "" {
+			b.scope = opts.Scope
+		}
+	}
+	return b
+}
+
+func (b bridge) logger() log.Logger {
+	if b.loggerProvider == nil {
+		return global.GetLoggerProvider().Logger(b.scope)
+	}
+	return b.loggerProvider.Logger(b.scope)
+}
+
+// logrusHook represents an internal logrus.Hook emitting the entries as OpenTelemetry log records
+type logrusHook struct {
+	bridge bridge
+}
+
+func (h logrusHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h logrusHook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var record log.Record
+	record.SetTimestamp(entry.Time)
+	record.SetSeverity(logrusSeverity(entry.Level))
+	record.SetSeverityText(entry.Level.String())
+	record.SetBody(log.StringValue(entry.Message))
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		record.AddAttributes(log.KeyValue{Key: key, Value: logValue(entry.Data[key])})
+	}
+
+	h.bridge.logger().Emit(ctx, record)
+	return nil
+}
+
+func logrusSeverity(level logrus.Level) log.Severity {
+	switch level {
+	case logrus.PanicLevel:
+		return log.SeverityFatal4
+	case logrus.FatalLevel:
+		return log.SeverityFatal
+	case logrus.ErrorLevel:
+		return log.SeverityError
+	case logrus.WarnLevel:
+		return log.SeverityWarn
+	case logrus.InfoLevel:
+		return log.SeverityInfo
+	case logrus.DebugLevel:
+		return log.SeverityDebug
+	default:
+		return log.SeverityTrace
+	}
+}
+
+// slogHandler represents an internal slog.Handler emitting the records as OpenTelemetry log records
+type slogHandler struct {
+	bridge bridge
+	attrs  []log.KeyValue
+	prefix string // the open groups, each followed by a dot
+}
+
+func (h slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	var record log.Record
+	record.SetSeverity(slogSeverity(level))
+	return h.bridge.logger().Enabled(ctx, record)
+}
+
+func (h slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var record log.Record
+	record.SetTimestamp(r.Time)
+	record.SetSeverity(slogSeverity(r.Level))
+	record.SetSeverityText(r.Level.String())
+	record.SetBody(log.StringValue(r.Message))
+	record.AddAttributes(h.attrs...)
+	r.Attrs(func(attr slog.Attr) bool {
+		record.AddAttributes(slogKeyValues(h.prefix, attr)...)
+		return true
+	})
+
+	h.bridge.logger().Emit(ctx, record)
+	return nil
+}
+
+func (h slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	all := append([]log.KeyValue{}, h.attrs...)
+	for _, attr := range attrs {
+		all = append(all, slogKeyValues(h.prefix, attr)...)
+	}
+	return slogHandler{bridge: h.bridge, attrs: all, prefix: h.prefix}
+}
+
+func (h slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return slogHandler{bridge: h.bridge, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// slogSeverity maps the slog levels to the OpenTelemetry severities, e.g. slog.LevelInfo to log.SeverityInfo,
+// as recommended by the OpenTelemetry specification.
+func slogSeverity(level slog.Level) log.Severity {
+	severity := log.Severity(level + 9)
+	switch {
+	case severity < log.SeverityTrace1:
+		return log.SeverityTrace1
+	case severity > log.SeverityFatal4:
+		return log.SeverityFatal4
+	}
+	return severity
+}
+
+// slogKeyValues flattens attr, the keys of the group members being prefixed by the group name.
+func slogKeyValues(prefix string, attr slog.Attr) []log.KeyValue {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return []log.KeyValue{{Key: prefix + attr.Key, Value: slogValue(attr.Value)}}
+	}
+
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+	var keyValues []log.KeyValue
+	for _, member := range attr.Value.Group() {
+		keyValues = append(keyValues, slogKeyValues(prefix, member)...)
+	}
+	return keyValues
+}
+
+func slogValue(v slog.Value) log.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return log.StringValue(v.String())
+	case slog.KindInt64:
+		return log.Int64Value(v.Int64())
+	case slog.KindUint64:
+		return log.Int64Value(int64(v.Uint64()))
+	case slog.KindFloat64:
+		return log.Float64Value(v.Float64())
+	case slog.KindBool:
+		return log.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return log.StringValue(v.Duration().String())
+	case slog.KindTime:
+		return log.StringValue(v.Time().Format(time.RFC3339Nano))
+	default:
+		return logValue(v.Any())
+	}
+}
+
+// logValue converts a log field value to a log record attribute value, the unsupported types being formatted.
+func logValue(v interface{}) log.Value {
+	switch v := v.(type) {
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case int:
+		return log.IntValue(v)
+	case int32:
+		return log.Int64Value(int64(v))
+	case int64:
+		return log.Int64Value(v)
+	case float32:
+		return log.Float64Value(float64(v))
+	case float64:
+		return log.Float64Value(v)
+	case []byte:
+		return log.BytesValue(v)
+	case error:
+		return log.StringValue(v.Error())
+	case fmt.Stringer:
+		return log.StringValue(v.String())
+	default:
+		return log.StringValue(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package otellog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/AccelByte/observability-go-sdk/trace"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc"
+)
+
+// logRecorder is an in-memory log exporter.
+type logRecorder struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (r *logRecorder) Export(_ context.Context, records []sdklog.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range records {
+		r.records = append(r.records, record.Clone())
+	}
+	return nil
+}
+
+func (r *logRecorder) Shutdown(context.Context) error {
+	return nil
+}
+
+func (r *logRecorder) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (r *logRecorder) recorded() []sdklog.Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records
+}
+
+var propagatedSpanContext = oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+	TraceID:    oteltrace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     oteltrace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: oteltrace.FlagsSampled,
+	Remote:     true,
+})
+
+// newLogBridgeClient returns a trace client whose logrus logger emits to the returned recorder through the hook.
+func newLogBridgeClient() (*trace.Client, *Opts, *logRecorder) {
+	recorder := &logRecorder{}
+	opts := &Opts{LoggerProvider: sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(recorder)))}
+	logger := logrus.New()
+	logger.Out = io.Discard
+	logger.AddHook(NewLogrusHook(opts))
+	return trace.NewClient("test", "test", &trace.Opts{Logger: logger}), opts, recorder
+}
+
+func recordAttributes(record sdklog.Record) map[string]log.Value {
+	attributes := map[string]log.Value{}
+	record.WalkAttributes(func(kv log.KeyValue) bool {
+		attributes[kv.Key] = kv.Value
+		return true
+	})
+	return attributes
+}
+
+func TestLogrusHook(t *testing.T) {
+	client, _, recorder := newLogBridgeClient()
+
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), propagatedSpanContext)
+	client.LoggerFromContext(ctx).WithField("count", 3).WithError(errors.New("boom")).Warn("ban added")
+
+	records := recorder.recorded()
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, log.SeverityWarn, record.Severity())
+	assert.Equal(t, "warning", record.SeverityText())
+	assert.Equal(t, "ban added", record.Body().AsString())
+	assert.Equal(t, propagatedSpanContext.TraceID(), record.TraceID())
+	assert.Equal(t, propagatedSpanContext.SpanID(), record.SpanID())
+
+	attributes := recordAttributes(record)
+	assert.Equal(t, int64(3), attributes["count"].AsInt64())
+	assert.Equal(t, "boom", attributes[logrus.ErrorKey].AsString())
+	assert.Equal(t, propagatedSpanContext.TraceID().String(), attributes[trace.LogFieldTraceID].AsString())
+}
+
+func TestLogrusHookStructuredLogger(t *testing.T) {
+	client, _, recorder := newLogBridgeClient()
+
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), propagatedSpanContext)
+	client.LogTraceError(ctx, errors.New("boom"), "failed")
+
+	records := recorder.recorded()
+	require.Len(t, records, 1)
+	assert.Equal(t, log.SeverityError, records[0].Severity())
+	assert.Equal(t, propagatedSpanContext.TraceID(), records[0].TraceID())
+}
+
+func TestSlogHandler(t *testing.T) {
+	_, opts, recorder := newLogBridgeClient()
+	logger := slog.New(NewSlogHandler(opts)).With("user", "alice").WithGroup("req")
+
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), propagatedSpanContext)
+	logger.DebugContext(ctx, "request", "method", "GET", slog.Group("ban", "reason", "cheat", "days", 7))
+
+	records := recorder.recorded()
+	require.Len(t, records, 1)
+	record := records[0]
+	assert.Equal(t, log.SeverityDebug, record.Severity())
+	assert.Equal(t, "request", record.Body().AsString())
+	assert.Equal(t, propagatedSpanContext.TraceID(), record.TraceID())
+
+	attributes := recordAttributes(record)
+	assert.Equal(t, "alice", attributes["user"].AsString())
+	assert.Equal(t, "GET", attributes["req.method"].AsString())
+	assert.Equal(t, "cheat", attributes["req.ban.reason"].AsString())
+	assert.Equal(t, int64(7), attributes["req.ban.days"].AsInt64())
+}
+
+func TestSlogSeverity(t *testing.T) {
+	assert.Equal(t, log.SeverityDebug, slogSeverity(slog.LevelDebug))
+	assert.Equal(t, log.SeverityInfo, slogSeverity(slog.LevelInfo))
+	assert.Equal(t, log.SeverityWarn, slogSeverity(slog.LevelWarn))
+	assert.Equal(t, log.SeverityError, slogSeverity(slog.LevelError))
+	assert.Equal(t, log.SeverityTrace1, slogSeverity(slog.LevelDebug-10))
+	assert.Equal(t, log.SeverityFatal4, slogSeverity(slog.LevelError+100))
+}
+
+func TestNewWriterLoggerProvider(t *testing.T) {
+	var buf bytes.Buffer
+	lp, err := NewWriterLoggerProvider("test", &buf)
+	require.NoError(t, err)
+
+	slog.New(NewSlogHandler(&Opts{LoggerProvider: lp})).Info("ban added", "user", "alice")
+	shutdownFunc(context.Background(), lp)()
+
+	assert.Contains(t, buf.String(), `"ban added"`)
+	assert.Contains(t, buf.String(), `"alice"`)
+}
+
+// otlpLogReceiver is an in-process OTLP logs receiver recording the exported log bodies.
+type otlpLogReceiver struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (r *otlpLogReceiver) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, resourceLogs := range req.GetResourceLogs() {
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			for _, record := range scopeLogs.GetLogRecords() {
+				r.bodies = append(r.bodies, record.GetBody().GetStringValue())
+			}
+		}
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (r *otlpLogReceiver) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bodies
+}
+
+func TestNewLoggerProviderGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver := &otlpLogReceiver{}
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, receiver)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	lp, err := NewLoggerProvider(context.Background(), "test", trace.TracerOpts{
+		Endpoint: listener.Addr().String(),
+		Insecure: true,
+		Gzip:     true,
+	})
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.Out = io.Discard
+	logger.AddHook(NewLogrusHook(&Opts{LoggerProvider: lp}))
+	logger.Info("exported")
+	shutdownFunc(context.Background(), lp)()
+
+	assert.Equal(t, []string{"exported"}, receiver.received())
+}
+
+func TestNewLoggerProviderUnsupportedProtocol(t *testing.T) {
+	_, err := NewLoggerProvider(context.Background(), "test", trace.TracerOpts{Protocol: "thrift"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+// Package otellog bridges the logrus and slog logs to OpenTelemetry Logs, so that the log records are shipped
+// to the collector with the trace context of the spans. It is a module of its own since the OpenTelemetry
+// Logs API is not stable yet, the trace package does not depend on it.
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/AccelByte/observability-go-sdk/trace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"google.golang.org/grpc/credentials"
+)
+
+// SetUpLogExporter sets up the global logger provider for serviceName, shipping the log records emitted by
+// the logrus hook and the slog handler of NewLogrusHook and NewSlogHandler to an OTLP collector.
+// opts is the same as the one of trace.SetUpTracerWithOptions, except that URLPath defaults to /v1/logs,
+// ConnectTimeout is ignored, and Sampler and Propagators are not used.
+// It returns a function flushing and shutting down the logger provider.
+func SetUpLogExporter(ctx context.Context, serviceName string, opts trace.TracerOpts) (func(), error) {
+	lp, err := NewLoggerProvider(ctx, serviceName, opts)
+	if err != nil {
+		return nil, err
+	}
+	global.SetLoggerProvider(lp)
+
+	return shutdownFunc(ctx, lp), nil
+}
+
+// SetUpLogWriterExporter sets up the global logger provider for serviceName, writing the log records
+// as JSON to w, e.g. os.Stdout or a file, for local development.
+// It returns a function flushing and shutting down the logger provider.
+func SetUpLogWriterExporter(serviceName string, w io.Writer) (func(), error) {
+	lp, err := NewWriterLoggerProvider(serviceName, w)
+	if err != nil {
+		return nil, err
+	}
+	global.SetLoggerProvider(lp)
+
+	return shutdownFunc(context.Background(), lp), nil
+}
+
+// NewLoggerProvider creates a logger provider for serviceName exporting to an OTLP collector configured by opts,
+// see SetUpLogExporter. Use it as Opts.LoggerProvider to leave the global logger provider untouched.
+func NewLoggerProvider(ctx context.Context, serviceName string, opts trace.TracerOpts) (*sdklog.LoggerProvider, error) {
+	var exporter sdklog.Exporter
+	var err error
+	switch opts.Protocol {
+	case trace.ExporterProtocolGRPC, "":
+		exporter, err = otlploggrpc.New(ctx, grpcOptions(opts)...)
+	case trace.ExporterProtocolHTTP:
+		exporter, err = otlploghttp.New(ctx, httpOptions(opts)...)
+	default:
+		return nil, fmt.Errorf("unsupported exporter protocol %q", opts.Protocol)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up log exporter: %w", err)
+	}
+
+	return newLoggerProvider(serviceName, sdklog.NewBatchProcessor(exporter, processorOptions(opts.Batch)...))
+}
+
+// NewWriterLoggerProvider creates a logger provider for serviceName writing the log records as JSON to w,
+// see SetUpLogWriterExporter. Use it as Opts.LoggerProvider to leave the global logger provider untouched.
+func NewWriterLoggerProvider(serviceName string, w io.Writer) (*sdklog.LoggerProvider, error) {
+	exporter, err := stdoutlog.New(stdoutlog.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up log exporter: %w", err)
+	}
+
+	return newLoggerProvider(serviceName, sdklog.NewSimpleProcessor(exporter))
+}
+
+func newLoggerProvider(serviceName string, processor sdklog.Processor) (*sdklog.LoggerProvider, error) {
+	resc, err := trace.NewResource(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdklog.NewLoggerProvider(sdklog.WithResource(resc), sdklog.WithProcessor(processor)), nil
+}
+
+func shutdownFunc(ctx context.Context, lp *sdklog.LoggerProvider) func() {
+	return func() {
+		if err := lp.ForceFlush(ctx); err != nil {
+			log.Print(err)
+		}
+		if err := lp.Shutdown(ctx); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+func grpcOptions(opts trace.TracerOpts) []otlploggrpc.Option {
+	options := []otlploggrpc.Option{otlploggrpc.WithEndpoint(opts.Endpoint)}
+	switch {
+	case opts.Insecure:
+		options = append(options, otlploggrpc.WithInsecure())
+	case opts.TLSConfig != nil:
+		options = append(options, otlploggrpc.WithTLSCredentials(credentials.NewTLS(opts.TLSConfig)))
+	}
+	if len(opts.Headers) > 0 {
+		options = append(options, otlploggrpc.WithHeaders(opts.Headers))
+	}
+	if opts.Gzip {
+		options = append(options, otlploggrpc.WithCompressor("gzip"))
+	}
+	if opts.ExportTimeout > 0 {
+		options = append(options, otlploggrpc.WithTimeout(opts.ExportTimeout))
+	}
+	if opts.Retry != nil {
+		options = append(options, otlploggrpc.WithRetry(otlploggrpc.RetryConfig(*opts.Retry)))
+	}
+	return options
+}
+
+func httpOptions(opts trace.TracerOpts) []otlploghttp.Option {
+	options := []otlploghttp.Option{otlploghttp.WithEndpoint(opts.Endpoint)}
+	switch {
+	case opts.Insecure:
+		options = append(options, otlploghttp.WithInsecure())
+	case opts.TLSConfig != nil:
+		options = append(options, otlploghttp.WithTLSClientConfig(opts.TLSConfig))
+	}
+	if len(opts.Headers) > 0 {
+		options = append(options, otlploghttp.WithHeaders(opts.Headers))
+	}
+	if opts.Gzip {
+		options = append(options, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+	if opts.ExportTimeout > 0 {
+		options = append(options, otlploghttp.WithTimeout(opts.ExportTimeout))
+	}
+	if opts.Retry != nil {
+		options = append(options, otlploghttp.WithRetry(otlploghttp.RetryConfig(*opts.Retry)))
+	}
+	return options
+}
+
+func processorOptions(b trace.BatchOpts) []sdklog.BatchProcessorOption {
+	var options []sdklog.BatchProcessorOption
+	if b.MaxQueueSize > 0 {
+		options = append(options, sdklog.WithMaxQueueSize(b.MaxQueueSize))
+	}
+	if b.MaxExportBatchSize > 0 {
+		options = append(options, sdklog.WithExportMaxBatchSize(b.MaxExportBatchSize))
+	}
+	if b.BatchTimeout > 0 {
+		options = append(options, sdklog.WithExportInterval(b.BatchTimeout))
+	}
+	if b.ExportTimeout > 0 {
+		options = append(options, sdklog.WithExportTimeout(b.ExportTimeout))
+	}
+	return options
+}
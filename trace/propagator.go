@@ -0,0 +1,112 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Propagator is a context propagation format, named like in the OTEL_PROPAGATORS environment variable.
+type Propagator string
+
+const (
+	PropagatorTraceContext Propagator = "tracecontext" // W3C traceparent and tracestate headers
+	PropagatorBaggage      Propagator = "baggage"      // W3C baggage header
+	PropagatorB3           Propagator = "b3"           // B3 single header
+	PropagatorB3Multi      Propagator = "b3multi"      // B3 X-B3-* headers
+	PropagatorJaeger       Propagator = "jaeger"       // uber-trace-id header
+)
+
+// DefaultPropagators are the formats injected when none is configured.
+var DefaultPropagators = []Propagator{PropagatorB3Multi}
+
+// NewPropagator creates a propagator injecting the given formats, or DefaultPropagators if none is given.
+// Extraction is lenient: the span context is read from the first valid header among the injected formats
+// then W3C TraceContext, B3 and Jaeger, and the W3C baggage is always read.
+func NewPropagator(propagators ...Propagator) (propagation.TextMapPropagator, error) {
+	if len(propagators) == 0 {
+		propagators = DefaultPropagators
+	}
+
+	var injectors []propagation.TextMapPropagator
+	var b3Encoding b3.Encoding
+	for _, p := range propagators {
+		switch p {
+		case PropagatorTraceContext:
+			injectors = append(injectors, propagation.TraceContext{})
+		case PropagatorBaggage:
+			injectors = append(injectors, propagation.Baggage{})
+		case PropagatorB3:
+			b3Encoding |= b3.B3SingleHeader
+		case PropagatorB3Multi:
+			b3Encoding |= b3.B3MultipleHeader
+		case PropagatorJaeger:
+			injectors = append(injectors, jaeger.Jaeger{})
+		default:
+			return nil, fmt.Errorf("unsupported propagator %q", p)
+		}
+	}
+	if b3Encoding != 0 {
+		injectors = append(injectors, b3.New(b3.WithInjectEncoding(b3Encoding)))
+	}
+
+	extractors := append([]propagation.TextMapPropagator{}, injectors...)
+	extractors = append(extractors, propagation.TraceContext{}, b3.New(), jaeger.Jaeger{})
+
+	return lenientPropagator{
+		injector:   propagation.NewCompositeTextMapPropagator(injectors...),
+		extractors: extractors,
+	}, nil
+}
+
+// SetPropagators sets the global propagator injecting the given formats, see NewPropagator.
+func SetPropagators(propagators ...Propagator) error {
+	propagator, err := NewPropagator(propagators...)
+	if err != nil {
+		return err
+	}
+	otel.SetTextMapPropagator(propagator)
+	return nil
+}
+
+func setDefaultPropagator() {
+	_ = SetPropagators()
+}
+
+// lenientPropagator represents an internal propagator injecting a fixed set of formats
+// and extracting the span context from any supported format
+type lenientPropagator struct {
+	injector   propagation.TextMapPropagator
+	extractors []propagation.TextMapPropagator
+}
+
+func (p lenientPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	p.injector.Inject(ctx, carrier)
+}
+
+func (p lenientPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	ctx = propagation.Baggage{}.Extract(ctx, carrier)
+
+	for _, extractor := range p.extractors {
+		// the extraction starts from an empty context to not mistake a local span for an extracted one
+		sc := trace.SpanContextFromContext(extractor.Extract(context.Background(), carrier))
+		if sc.IsValid() {
+			return trace.ContextWithRemoteSpanContext(ctx, sc)
+		}
+	}
+	return ctx
+}
+
+// Fields returns the fields set by Inject, i.e. the ones of the injected formats only.
+func (p lenientPropagator) Fields() []string {
+	return p.injector.Fields()
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var propagatedSpanContext = oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+	TraceID:    oteltrace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     oteltrace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: oteltrace.FlagsSampled,
+	Remote:     true,
+})
+
+func TestPropagatorInjection(t *testing.T) {
+	member, err := baggage.NewMember("tenant", "accelbyte")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+	ctx := baggage.ContextWithBaggage(oteltrace.ContextWithRemoteSpanContext(context.Background(), propagatedSpanContext), bag)
+
+	propagator, err := NewPropagator(PropagatorTraceContext, PropagatorBaggage)
+	require.NoError(t, err)
+	header := http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get("traceparent"))
+	assert.Equal(t, "tenant=accelbyte", header.Get("baggage"))
+	assert.Empty(t, header.Get("X-B3-TraceId"))
+
+	propagator, err = NewPropagator()
+	require.NoError(t, err)
+	header = http.Header{}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", header.Get("X-B3-TraceId"))
+	assert.Empty(t, header.Get("traceparent"))
+}
+
+func TestPropagatorFields(t *testing.T) {
+	propagator, err := NewPropagator(PropagatorTraceContext, PropagatorBaggage)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, propagator.Fields())
+
+	propagator, err = NewPropagator(PropagatorB3)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b3"}, propagator.Fields())
+}
+
+func TestPropagatorLenientExtraction(t *testing.T) {
+	propagator, err := NewPropagator(PropagatorTraceContext)
+	require.NoError(t, err)
+
+	for name, header := range map[string]http.Header{
+		"tracecontext": {"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}},
+		"b3":           {"B3": {"4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1"}},
+		"b3multi": {
+			"X-B3-Traceid": {"4bf92f3577b34da6a3ce929d0e0e4736"},
+			"X-B3-Spanid":  {"00f067aa0ba902b7"},
+			"X-B3-Sampled": {"1"},
+		},
+		"jaeger": {"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"}},
+	} {
+		ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(header))
+		assert.Equal(t, propagatedSpanContext, oteltrace.SpanContextFromContext(ctx), name)
+	}
+
+	ctx := propagator.Extract(context.Background(), propagation.HeaderCarrier(http.Header{"Baggage": {"tenant=accelbyte"}}))
+	assert.Equal(t, "accelbyte", baggage.FromContext(ctx).Member("tenant").Value())
+	assert.False(t, oteltrace.SpanContextFromContext(ctx).IsValid())
+}
+
+func TestPropagatorUnsupported(t *testing.T) {
+	_, err := NewPropagator("xray")
+	assert.Error(t, err)
+}
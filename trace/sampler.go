@@ -0,0 +1,160 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplerOpts represents the head sampling configuration options.
+type SamplerOpts struct {
+	// Ratio is the fraction of traces sampled, between 0 and 1. It can be changed at runtime with Sampler.SetRatio.
+	Ratio float64
+	// ParentBased follows the sampling decision of the remote parent span when there is one,
+	// so that a trace is either fully sampled or not across services. The decision of a local
+	// parent span is always followed.
+	ParentBased bool
+	// RouteRatios overrides Ratio per route and HTTP method, with the same format as the excluded endpoints
+	// of InstrumentCommonAttributes, eg. key: /healthz , key2: GET , value: 0.01
+	// The route is the http.route attribute, or the span name if the attribute is not set when the span starts.
+	// The route ratios only apply to the root spans and to the spans with a remote parent.
+	RouteRatios map[string]map[string]float64
+	// AlwaysSampleErrors exports the spans ended with an error status even if they were not sampled.
+	AlwaysSampleErrors bool
+	// SlowThreshold exports the spans lasting at least SlowThreshold even if they were not sampled, 0 = disabled.
+	SlowThreshold time.Duration
+}
+
+// Sampler is a trace ID ratio based sampler with per route ratios, able to keep the erroneous and slow spans.
+// The spans that are not sampled are still recorded when AlwaysSampleErrors or SlowThreshold is set, to decide
+// when they end whether they must be exported. Such spans are exported alone: the sampling decision propagated
+// to their children and to the downstream services remains "not sampled".
+type Sampler struct {
+	ratio atomic.Uint64 // math.Float64bits of the ratio
+	opts  SamplerOpts
+}
+
+// NewSampler creates a new sampler configured by opts. Use it as TracerOpts.Sampler.
+func NewSampler(opts SamplerOpts) *Sampler {
+	s := &Sampler{opts: opts}
+	s.SetRatio(opts.Ratio)
+	return s
+}
+
+// SetRatio changes the default sampling ratio of the sampler, it is clamped between 0 and 1.
+func (s *Sampler) SetRatio(ratio float64) {
+	s.ratio.Store(math.Float64bits(math.Max(0, math.Min(1, ratio))))
+}
+
+// Ratio returns the current default sampling ratio of the sampler.
+func (s *Sampler) Ratio() float64 {
+	return math.Float64frombits(s.ratio.Load())
+}
+
+// ShouldSample implements sdktrace.Sampler.
+func (s *Sampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	parent := trace.SpanContextFromContext(p.ParentContext)
+	result := sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: parent.TraceState()}
+
+	// the children of a local span always follow its decision, so that the traces are not broken
+	// by the child spans, which have no route, being sampled with the default ratio
+	sampled := false
+	if parent.IsValid() && (!parent.IsRemote() || s.opts.ParentBased) {
+		sampled = parent.IsSampled()
+	} else {
+		sampled = traceIDSampled(p.TraceID, s.routeRatio(p))
+	}
+
+	switch {
+	case sampled:
+		result.Decision = sdktrace.RecordAndSample
+	case s.keepsSlowOrErrors():
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+// Description implements sdktrace.Sampler.
+func (s *Sampler) Description() string {
+	return fmt.Sprintf("Sampler{ratio:%g,parentBased:%t,alwaysSampleErrors:%t,slowThreshold:%s}",
+		s.Ratio(), s.opts.ParentBased, s.opts.AlwaysSampleErrors, s.opts.SlowThreshold)
+}
+
+func (s *Sampler) routeRatio(p sdktrace.SamplingParameters) float64 {
+	if len(s.opts.RouteRatios) == 0 {
+		return s.Ratio()
+	}
+
+	route, method := p.Name, ""
+	for _, attr := range p.Attributes {
+		switch attr.Key {
+		case semconv.HTTPRouteKey:
+			route = attr.Value.AsString()
+		case HTTPMethodKey:
+			method = attr.Value.AsString()
+		}
+	}
+	if ratio, ok := s.opts.RouteRatios[route][method]; ok {
+		return ratio
+	}
+	return s.Ratio()
+}
+
+func (s *Sampler) keepsSlowOrErrors() bool {
+	return s.opts.AlwaysSampleErrors || s.opts.SlowThreshold > 0
+}
+
+// keeps returns whether a span that was not sampled must be exported anyway.
+func (s *Sampler) keeps(span sdktrace.ReadOnlySpan) bool {
+	if s.opts.AlwaysSampleErrors && span.Status().Code == codes.Error {
+		return true
+	}
+	return s.opts.SlowThreshold > 0 && span.EndTime().Sub(span.StartTime()) >= s.opts.SlowThreshold
+}
+
+// traceIDSampled makes the same decision as sdktrace.TraceIDRatioBased, for a ratio that can change at runtime.
+func traceIDSampled(traceID trace.TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	bound := uint64(ratio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < bound
+}
+
+// keepingSpanProcessor forwards the spans that were not sampled but must be kept by the sampler
+// to the wrapped processor, which only exports the sampled spans.
+type keepingSpanProcessor struct {
+	sdktrace.SpanProcessor
+	sampler *Sampler
+}
+
+func (p keepingSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		if !p.sampler.keeps(s) {
+			return
+		}
+		s = keptSpan{s}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// keptSpan reports a recorded span as sampled to be exported.
+type keptSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s keptSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
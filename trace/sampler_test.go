@@ -0,0 +1,115 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestSamplerRouteRatios(t *testing.T) {
+	sampler := NewSampler(SamplerOpts{
+		Ratio:       0,
+		RouteRatios: map[string]map[string]float64{"/bans": {"GET": 1}},
+	})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	tracer := tp.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "GET /bans",
+		oteltrace.WithAttributes(semconv.HTTPRoute("/bans"), HTTPMethodKey.String("GET")))
+	assert.True(t, span.SpanContext().IsSampled())
+
+	_, span = tracer.Start(context.Background(), "POST /bans",
+		oteltrace.WithAttributes(semconv.HTTPRoute("/bans"), HTTPMethodKey.String("POST")))
+	assert.False(t, span.SpanContext().IsSampled())
+
+	sampler.SetRatio(1)
+	_, span = tracer.Start(context.Background(), "POST /bans",
+		oteltrace.WithAttributes(semconv.HTTPRoute("/bans"), HTTPMethodKey.String("POST")))
+	assert.True(t, span.SpanContext().IsSampled())
+	assert.Equal(t, float64(1), sampler.Ratio())
+}
+
+func TestSamplerFollowsLocalParent(t *testing.T) {
+	testCases := []struct {
+		name        string
+		routeRatio  float64
+		ratio       float64
+		wantSampled bool
+	}{
+		{name: "sampled route", routeRatio: 1, ratio: 0, wantSampled: true},
+		{name: "dropped route", routeRatio: 0, ratio: 1, wantSampled: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracer := sdktrace.NewTracerProvider(sdktrace.WithSampler(NewSampler(SamplerOpts{
+				Ratio:       tc.ratio,
+				RouteRatios: map[string]map[string]float64{"/healthz": {"GET": tc.routeRatio}},
+			}))).Tracer("test")
+
+			ctx, root := tracer.Start(context.Background(), "GET /healthz",
+				oteltrace.WithAttributes(semconv.HTTPRoute("/healthz"), HTTPMethodKey.String("GET")))
+			_, child := tracer.Start(ctx, "db.query")
+
+			assert.Equal(t, tc.wantSampled, root.SpanContext().IsSampled())
+			assert.Equal(t, tc.wantSampled, child.SpanContext().IsSampled())
+		})
+	}
+}
+
+func TestSamplerParentBased(t *testing.T) {
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSampler(NewSampler(SamplerOpts{Ratio: 1, ParentBased: true}))).Tracer("test")
+
+	parent := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID: oteltrace.TraceID{1},
+		SpanID:  oteltrace.SpanID{1},
+		Remote:  true,
+	})
+	_, span := tracer.Start(oteltrace.ContextWithRemoteSpanContext(context.Background(), parent), "child")
+	assert.False(t, span.SpanContext().IsSampled())
+	assert.False(t, span.IsRecording())
+
+	_, span = tracer.Start(context.Background(), "root")
+	assert.True(t, span.SpanContext().IsSampled())
+}
+
+func TestSamplerKeepsErrorsAndSlowSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp, err := newTraceproviderWithExporter("test", exporter, TracerOpts{
+		Sampler: NewSampler(SamplerOpts{Ratio: 0, AlwaysSampleErrors: true, SlowThreshold: time.Second}),
+	})
+	require.NoError(t, err)
+	tracer := tp.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "fast")
+	assert.True(t, span.IsRecording())
+	span.End()
+
+	_, span = tracer.Start(context.Background(), "error")
+	span.RecordError(errors.New("failed"))
+	span.SetStatus(codes.Error, "failed")
+	span.End()
+
+	_, span = tracer.Start(context.Background(), "slow", oteltrace.WithTimestamp(time.Now().Add(-2*time.Second)))
+	span.End()
+
+	require.NoError(t, tp.ForceFlush(context.Background()))
+	var names []string
+	for _, stub := range exporter.GetSpans() {
+		names = append(names, stub.Name)
+		assert.True(t, stub.SpanContext.IsSampled())
+	}
+	assert.Equal(t, []string{"error", "slow"}, names)
+}
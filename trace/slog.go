@@ -0,0 +1,45 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogHandler wraps next to add the trace context of the record context, as well as the flight id,
+// user id and namespace of its baggage, to every record. The fields are named after DefaultLogFieldNames.
+// Use it with the context aware slog functions:
+//
+//	logger := slog.New(trace.NewSlogHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.InfoContext(ctx, "ban added")
+func NewSlogHandler(next slog.Handler) slog.Handler {
+	return slogHandler{next}
+}
+
+// slogHandler represents an internal slog.Handler injecting the trace context in the records
+type slogHandler struct {
+	next slog.Handler
+}
+
+func (h slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := DefaultLogFieldNames.fields(ctx, "")
+	for _, key := range sortedKeys(fields) {
+		r.AddAttrs(slog.Any(key, fields[key]))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return slogHandler{h.next.WithAttrs(attrs)}
+}
+
+func (h slogHandler) WithGroup(name string) slog.Handler {
+	return slogHandler{h.next.WithGroup(name)}
+}
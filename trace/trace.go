@@ -6,67 +6,105 @@ package trace
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
-	"go.opentelemetry.io/contrib/propagators/b3"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.11.0"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/grpc"
 )
 
 const (
-	LogFieldTraceID = "trace_id"
+	LogFieldTraceID     = "trace_id"
+	LogFieldSpanID      = "span_id"
+	LogFieldTraceFlags  = "trace_flags"
+	LogFieldServiceName = "service.name"
+	LogFieldFlightID    = "flight_id"
+	LogFieldUserID      = "user_id"
+	LogFieldNamespace   = "namespace"
 )
 
-var (
-	traceProviderName string
-	serviceName       string
-)
+var defaultClient = &Client{}
 
+// Initialize initializes the default client used by the package level functions.
+// Use NewClient instead to run several independent instances in the same binary.
 func Initialize(traceProvider, service string) {
-	traceProviderName = traceProvider
-	serviceName = service
+	defaultClient = NewClient(traceProvider, service, nil)
 }
 
 // SetUpTracer sets up a GRPC reciever for serviceName with url as the endpoint of the collector.
-// If a connection is not establised within connectTimeout, it is aborted and returns an error
+// If a connection is not establised within connectTimeout, it is aborted and returns an error.
+// Use SetUpTracerWithOptions to reach a collector over TLS, with custom headers or over OTLP/HTTP.
 func SetUpTracer(ctx context.Context, url string, connectTimeout time.Duration) (func(), error) {
-	ctx, cancel := context.WithTimeout(ctx, connectTimeout)
-	defer cancel()
+	return SetUpTracerWithOptions(ctx, TracerOpts{Endpoint: url, Insecure: true, ConnectTimeout: connectTimeout})
+}
 
-	client := otlptracegrpc.NewClient(
-		otlptracegrpc.WithInsecure(),
-		otlptracegrpc.WithEndpoint(url),
-		otlptracegrpc.WithDialOption(grpc.WithBlock()))
+// SetUpTracerWithExporter sets up the global tracer provider for serviceName with a custom span exporter,
+// using the same resource and propagator as SetUpTracer. It is mainly used to keep spans in memory in tests,
+// see the tracetest package.
+func SetUpTracerWithExporter(exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	return setupTraceproviderWithExporter(defaultClient.serviceName, exporter, TracerOpts{})
+}
+
+// provider is the flushing and shutdown part of the tracer provider.
+type provider interface {
+	ForceFlush(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+}
+
+func shutdownFunc(ctx context.Context, p provider) func() {
+	return func() {
+		if err := p.ForceFlush(ctx); err != nil {
+			log.Print(err)
+		}
+		if err := p.Shutdown(ctx); err != nil {
+			log.Print(err)
+		}
+	}
+}
 
-	exporter, err := otlptrace.New(ctx, client)
+func setupTraceproviderWithExporter(serviceName string, exporter sdktrace.SpanExporter, opts TracerOpts) (*sdktrace.TracerProvider, error) {
+	propagator, err := NewPropagator(opts.Propagators...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to set up exporter: %w", err)
+		return nil, err
 	}
 
-	tp, err := setupTraceproviderWithExporter(serviceName, exporter)
+	tp, err := newTraceproviderWithExporter(serviceName, exporter, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return func() {
-		if err := tp.ForceFlush(ctx); err != nil {
-			log.Print(err)
-		}
-		if err := tp.Shutdown(ctx); err != nil {
-			log.Print(err)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	return tp, nil
+}
+
+// newTraceproviderWithExporter creates a tracer provider batching the spans to exporter,
+// with the batch tuning and the sampler of opts. The spans are stamped with the request identity from the baggage.
+func newTraceproviderWithExporter(serviceName string, exporter sdktrace.SpanExporter, opts TracerOpts) (*sdktrace.TracerProvider, error) {
+	resc, err := NewResource(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	var processor sdktrace.SpanProcessor = sdktrace.NewBatchSpanProcessor(exporter, opts.Batch.spanProcessorOptions()...)
+	tpOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(resc), sdktrace.WithSpanProcessor(NewBaggageSpanProcessor())}
+	if opts.Sampler != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSampler(opts.Sampler))
+		if sampler, ok := opts.Sampler.(*Sampler); ok && sampler.keepsSlowOrErrors() {
+			processor = keepingSpanProcessor{SpanProcessor: processor, sampler: sampler}
 		}
-	}, nil
+	}
+	tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(processor))
+
+	return sdktrace.NewTracerProvider(tpOpts...), nil
 }
 
-func setupTraceproviderWithExporter(serviceName string, exporter sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+// NewResource returns the resource describing serviceName and its host, the one of the tracer providers set up
+// by this package. Use it to describe the same service in the other OpenTelemetry providers, e.g. otellog.
+func NewResource(serviceName string) (*resource.Resource, error) {
 	resc, err := resource.New(
 		context.Background(),
 		resource.WithOS(),
@@ -84,45 +122,33 @@ func setupTraceproviderWithExporter(serviceName string, exporter sdktrace.SpanEx
 		return nil, err
 	}
 
-	resc, err = resource.Merge(
+	return resource.Merge(
 		resource.Default(),
 		resc,
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(resc))
-	otel.SetTracerProvider(tp)
-	propagationB3 := b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader))
-
-	otel.SetTextMapPropagator(propagationB3)
-	return tp, nil
 }
 
 func NewRootSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	ctx, span := otel.Tracer(traceProviderName).Start(ctx, name, append(opts, trace.WithNewRoot())...)
-	ctx = LoggerAddField(ctx, LogFieldTraceID, TraceIDFromContext(ctx))
-
-	return ctx, span
+	return defaultClient.NewRootSpan(ctx, name, opts...)
 }
 
 func NewChildSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	return otel.Tracer(traceProviderName).Start(ctx, name, opts...)
+	return defaultClient.NewChildSpan(ctx, name, opts...)
 }
 
 func NewAutoNamedChildSpan(ctx context.Context, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
-	return otel.Tracer(traceProviderName).Start(ctx, getCallingFuncName(), opts...)
+	return defaultClient.Tracer().Start(ctx, getCallingFuncName(), opts...)
 }
 
 func SpanFromContext(ctx context.Context) trace.Span {
 	return trace.SpanFromContext(ctx)
 }
 
+// TraceIDFromContext returns the hex encoded trace id of the context span, or an empty string if there is no valid span.
 func TraceIDFromContext(ctx context.Context) string {
-	traceID := trace.SpanFromContext(ctx).SpanContext().TraceID().String()
-	if traceID == "" {
-		log.Println("trace_id not found")
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ""
 	}
-	return traceID
+	return sc.TraceID().String()
 }
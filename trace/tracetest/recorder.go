@@ -0,0 +1,138 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+// Package tracetest provides an in-memory span recorder to unit test the trace instrumentation
+// without running a collector.
+package tracetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AccelByte/observability-go-sdk/trace"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	sdktracetest "go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Span is a finished span recorded by the Recorder.
+type Span struct {
+	Name        string
+	SpanContext oteltrace.SpanContext
+	Parent      oteltrace.SpanContext
+	SpanKind    oteltrace.SpanKind
+	Attributes  map[attribute.Key]attribute.Value
+	Events      []sdktrace.Event
+	Links       []sdktrace.Link
+	Status      sdktrace.Status
+}
+
+// Attribute returns the string representation of the span attribute with the given key,
+// and false if the span does not have it.
+func (s Span) Attribute(key attribute.Key) (string, bool) {
+	v, ok := s.Attributes[key]
+	if !ok {
+		return "", false
+	}
+	return v.Emit(), true
+}
+
+// IsChildOf reports whether s was started as a direct child of parent.
+func (s Span) IsChildOf(parent Span) bool {
+	return s.Parent.SpanID() == parent.SpanContext.SpanID() && s.Parent.TraceID() == parent.SpanContext.TraceID()
+}
+
+// Recorder keeps the spans exported by the tracer provider in memory.
+type Recorder struct {
+	t              testing.TB
+	exporter       *sdktracetest.InMemoryExporter
+	tracerProvider *sdktrace.TracerProvider
+}
+
+// NewRecorder installs an in-memory exporter as the global tracer provider, through the same resource
+// and propagator setup as trace.SetUpTracer. When the test finishes, the tracer provider is shut down
+// and the previous global tracer provider and propagator are restored.
+func NewRecorder(t testing.TB) *Recorder {
+	t.Helper()
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	exporter := sdktracetest.NewInMemoryExporter()
+	tp, err := trace.SetUpTracerWithExporter(exporter)
+	if err != nil {
+		t.Fatalf("failed to set up the tracer provider: %v", err)
+	}
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+		if err := tp.Shutdown(context.Background()); err != nil {
+			t.Log(err)
+		}
+	})
+
+	return &Recorder{t: t, exporter: exporter, tracerProvider: tp}
+}
+
+// TracerProvider returns the recording tracer provider, i.e. to be passed to trace.NewClient.
+func (r *Recorder) TracerProvider() *sdktrace.TracerProvider {
+	return r.tracerProvider
+}
+
+// Ended flushes the tracer provider and returns every span ended so far, in the order they ended.
+// It fails the test if the flush fails.
+func (r *Recorder) Ended() []Span {
+	r.t.Helper()
+	r.flush()
+
+	stubs := r.exporter.GetSpans()
+	spans := make([]Span, 0, len(stubs))
+	for _, stub := range stubs {
+		spans = append(spans, fromStub(stub))
+	}
+	return spans
+}
+
+// SpanByName returns the first ended span with the given name, and false if none is found.
+func (r *Recorder) SpanByName(name string) (Span, bool) {
+	r.t.Helper()
+	for _, span := range r.Ended() {
+		if span.Name == name {
+			return span, true
+		}
+	}
+	return Span{}, false
+}
+
+// Reset drops every span recorded so far.
+func (r *Recorder) Reset() {
+	r.t.Helper()
+	r.flush()
+	r.exporter.Reset()
+}
+
+func (r *Recorder) flush() {
+	r.t.Helper()
+	if err := r.tracerProvider.ForceFlush(context.Background()); err != nil {
+		r.t.Fatalf("failed to flush the tracer provider: %v", err)
+	}
+}
+
+func fromStub(stub sdktracetest.SpanStub) Span {
+	attrs := make(map[attribute.Key]attribute.Value, len(stub.Attributes))
+	for _, kv := range stub.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return Span{
+		Name:        stub.Name,
+		SpanContext: stub.SpanContext,
+		Parent:      stub.Parent,
+		SpanKind:    stub.SpanKind,
+		Attributes:  attrs,
+		Events:      stub.Events,
+		Links:       stub.Links,
+		Status:      stub.Status,
+	}
+}
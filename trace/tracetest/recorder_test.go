@@ -0,0 +1,79 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package tracetest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AccelByte/observability-go-sdk/trace"
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func getBan(req *restful.Request, resp *restful.Response) {
+	ctx, span := trace.NewAutoNamedChildSpan(req.Request.Context())
+	defer span.End()
+
+	trace.LogTraceError(ctx, errors.New("request to DB failed"), "failed to get ban")
+	resp.WriteHeader(http.StatusInternalServerError)
+}
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder(t)
+
+	ws := new(restful.WebService)
+	ws.Route(ws.GET("/bans/{banId}").To(getBan))
+	container := restful.NewContainer()
+	container.Filter(func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		req.SetAttribute(trace.UserIDAttribute, "user-1")
+		chain.ProcessFilter(req, resp)
+	})
+	container.Filter(trace.InstrumentCommonAttributes("test", nil))
+	container.Add(ws)
+
+	req := httptest.NewRequest(http.MethodGet, "/bans/1", nil)
+	req.Header.Set(trace.FlightID, "flight-1")
+	container.ServeHTTP(httptest.NewRecorder(), req)
+
+	serverSpan, ok := recorder.SpanByName("/bans/{banId}")
+	require.True(t, ok)
+	userID, _ := serverSpan.Attribute("user.id")
+	flightID, _ := serverSpan.Attribute("flight.id")
+	statusCode, _ := serverSpan.Attribute(trace.HTTPStatusCodeKey)
+	assert.Equal(t, "user-1", userID)
+	assert.Equal(t, "flight-1", flightID)
+	assert.Equal(t, "500", statusCode)
+	assert.Equal(t, codes.Error, serverSpan.Status.Code)
+
+	childSpan, ok := recorder.SpanByName("tracetest.getBan")
+	require.True(t, ok)
+	assert.True(t, childSpan.IsChildOf(serverSpan))
+	assert.Equal(t, codes.Error, childSpan.Status.Code)
+	assert.Equal(t, "failed to get ban", childSpan.Status.Description)
+	require.Len(t, childSpan.Events, 1)
+	assert.Equal(t, "exception", childSpan.Events[0].Name)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestRecorderRestoresGlobals(t *testing.T) {
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+
+	t.Run("recording", func(t *testing.T) {
+		recorder := NewRecorder(t)
+		assert.True(t, otel.GetTracerProvider() == oteltrace.TracerProvider(recorder.TracerProvider()))
+	})
+
+	assert.True(t, previousProvider == otel.GetTracerProvider())
+	assert.Equal(t, previousPropagator, otel.GetTextMapPropagator())
+}
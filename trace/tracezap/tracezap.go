@@ -0,0 +1,46 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+// Package tracezap plugs a zap logger as the structured logger of the trace logging helpers.
+// It is a package of its own so that the trace package does not depend on zap.
+package tracezap
+
+import (
+	"sort"
+
+	"github.com/AccelByte/observability-go-sdk/trace"
+	"go.uber.org/zap"
+)
+
+// NewAdapter returns a trace.Logger writing to the zap logger.
+func NewAdapter(logger *zap.Logger) trace.Logger {
+	return adapter{logger}
+}
+
+// adapter represents an internal trace.Logger implementation for zap
+type adapter struct {
+	logger *zap.Logger
+}
+
+func (l adapter) WithFields(fields trace.Fields) trace.Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	zapFields := make([]zap.Field, 0, len(fields))
+	for _, key := range keys {
+		zapFields = append(zapFields, zap.Any(key, fields[key]))
+	}
+	return adapter{l.logger.With(zapFields...)}
+}
+
+func (l adapter) Info(msg string) {
+	l.logger.Info(msg)
+}
+
+func (l adapter) Error(err error, msg string) {
+	l.logger.Error(msg, zap.Error(err))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package tracezap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AccelByte/observability-go-sdk/trace"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAdapter(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	client := trace.NewClient("bans", "test", nil)
+	ctx := trace.ContextWithStructuredLogger(context.Background(), NewAdapter(zap.New(core)))
+	ctx = trace.LoggerAddField(ctx, "user", "alice")
+
+	client.LogTraceError(ctx, errors.New("failed"), "ban failed", logrus.Fields{"ban_id": "1"})
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, "ban failed", entry.Message)
+	fields := entry.ContextMap()
+	assert.Equal(t, "1", fields["ban_id"])
+	assert.Equal(t, "alice", fields["user"])
+	assert.Equal(t, "failed", fields["error"])
+
+	keys := map[string]int{}
+	for _, field := range entry.Context {
+		keys[field.Key]++
+	}
+	assert.Equal(t, 1, keys[trace.LogFieldServiceName])
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Transport is an http.RoundTripper that starts a client span for each outgoing request and
+// propagates it, together with the flight id from the context, to the downstream service.
+// The span ends once the response body is read to the end or closed, like with http.Client the body must be closed.
+type Transport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+// NewTransport wraps base to trace the outgoing requests with the global tracer provider.
+// If base is nil, http.DefaultTransport is used.
+func NewTransport(tracerName string, base http.RoundTripper) *Transport {
+	return NewClient(tracerName, defaultClient.serviceName, nil).NewTransport(base)
+}
+
+// NewTransport wraps base to trace the outgoing requests with the client tracer.
+// If base is nil, http.DefaultTransport is used.
+func (c *Client) NewTransport(base http.RoundTripper) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Transport{base: base, client: c}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.client.Tracer().Start(req.Context(), "HTTP "+req.Method,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(HTTPClientRequest(req)...),
+	)
+
+	// the request must not be modified by a RoundTripper, so the headers are set on a clone
+	req = req.Clone(ctx)
+	if flightID := FlightIDFromContext(ctx); flightID != "" && req.Header.Get(FlightID) == "" {
+		req.Header.Set(FlightID, flightID)
+	}
+	if flightID := req.Header.Get(FlightID); flightID != "" {
+		span.SetAttributes(attribute.String("flight.id", flightID))
+	}
+	t.client.Propagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+	span.SetStatus(HTTPClientStatus(resp.StatusCode))
+	if resp.Body == nil || resp.Body == http.NoBody {
+		span.End()
+		return resp, nil
+	}
+	// the span covers the reading of the body, it ends once the body is read or closed
+	resp.Body = newSpanBody(resp.Body, span)
+	return resp, nil
+}
+
+// spanBody represents an internal response body ending its span on io.EOF, on a read error or on Close
+type spanBody struct {
+	body io.ReadCloser
+	span oteltrace.Span
+	once sync.Once
+}
+
+// writableSpanBody represents an internal spanBody of a writable body, i.e. of a 101 Switching Protocols response
+type writableSpanBody struct {
+	*spanBody
+	writer io.Writer
+}
+
+func newSpanBody(body io.ReadCloser, span oteltrace.Span) io.ReadCloser {
+	b := &spanBody{body: body, span: span}
+	if w, ok := body.(io.Writer); ok {
+		return writableSpanBody{spanBody: b, writer: w}
+	}
+	return b
+}
+
+func (b *spanBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF):
+		b.end()
+	default:
+		b.span.RecordError(err)
+		b.span.SetStatus(codes.Error, err.Error())
+		b.end()
+	}
+	return n, err
+}
+
+func (b *spanBody) Close() error {
+	err := b.body.Close()
+	b.end()
+	return err
+}
+
+func (b *spanBody) end() {
+	b.once.Do(func() {
+		b.span.End()
+	})
+}
+
+func (b writableSpanBody) Write(p []byte) (int, error) {
+	return b.writer.Write(p)
+}
+
+// HTTPClientRequest returns the span attributes of an outgoing request.
+func HTTPClientRequest(req *http.Request) []attribute.KeyValue {
+	host, port := splitHostPort(req.URL.Host)
+
+	attrs := []attribute.KeyValue{
+		HTTPMethodKey.String(req.Method),
+		semconv.HTTPURL(redactedURL(req)),
+		flavor(req.Proto),
+		semconv.NetPeerName(host),
+	}
+	if port > 0 {
+		attrs = append(attrs, semconv.NetPeerPort(port))
+	}
+	if useragent := req.UserAgent(); useragent != "" {
+		attrs = append(attrs, HTTPUserAgentKey.String(useragent))
+	}
+	return attrs
+}
+
+// HTTPClientStatus returns the span status of an outgoing request response code.
+// Unlike HTTPServerStatus, 4xx responses are also errors from the client point of view.
+func HTTPClientStatus(code int) (codes.Code, string) {
+	if code < 100 || code >= 600 {
+		return codes.Error, fmt.Sprintf("Invalid HTTP status code %d", code)
+	}
+	if code >= 400 {
+		return codes.Error, ""
+	}
+	return codes.Unset, ""
+}
+
+// redactedURL returns the request URL without the user credentials.
+func redactedURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+	return u.String()
+}
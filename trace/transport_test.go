@@ -0,0 +1,102 @@
+// Copyright (c) 2023 AccelByte Inc. All Rights Reserved.
+// This is licensed software from AccelByte Inc, for limitations
+// and restrictions contact your company contract manager.
+
+package trace
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestTransport(t *testing.T) {
+	setDefaultPropagator()
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx := ContextWithFlightID(context.Background(), "flight-1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/bans/1", nil)
+	require.NoError(t, err)
+
+	resp, err := (&http.Client{Transport: client.NewTransport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET", spans[0].Name)
+	assert.Equal(t, oteltrace.SpanKindClient, spans[0].SpanKind)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "flight-1", received.Get(FlightID))
+	assert.Equal(t, spans[0].SpanContext.TraceID().String(), received.Get("X-B3-Traceid"))
+	assert.Empty(t, req.Header.Get(FlightID), "the original request must not be modified")
+}
+
+func TestTransportClientPropagator(t *testing.T) {
+	setDefaultPropagator()
+	propagator, err := NewPropagator(PropagatorTraceContext)
+	require.NoError(t, err)
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(),
+		Propagator:     propagator,
+	})
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	resp, err := (&http.Client{Transport: client.NewTransport(nil)}).Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.NotEmpty(t, received.Get("Traceparent"))
+	assert.Empty(t, received.Get("X-B3-Traceid"))
+}
+
+func TestTransportEndsSpanWithBody(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	client := NewClient("test", "test", &Opts{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ban"))
+	}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: client.NewTransport(nil)}
+
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	assert.Empty(t, exporter.GetSpans(), "the span must not end before the body is read")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ban", string(body))
+	assert.Len(t, exporter.GetSpans(), 1)
+	require.NoError(t, resp.Body.Close())
+	assert.Len(t, exporter.GetSpans(), 1)
+
+	resp, err = httpClient.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Len(t, exporter.GetSpans(), 2)
+}